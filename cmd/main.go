@@ -1,14 +1,46 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"filesystem-logger/internal/models"
 	"filesystem-logger/internal/scanner"
+	"filesystem-logger/internal/utils/csvexport"
 )
 
+// clearLine moves the cursor to the start of the line and erases it
+const clearLine = "\r\033[K"
+
 func main() {
+	showProgress := flag.Bool("progress", false, "show a live progress line while scanning")
+	preset := flag.String("preset", "", "start from a predefined config: "+strings.Join(models.PresetNames(), ", "))
+	fromStdin := flag.Bool("stdin", false, "scan the file paths read from stdin, one per line, instead of walking a directory")
+	statsPath := flag.String("stats", "", "append a row of scan statistics to this CSV file")
+	checkpointPath := flag.String("checkpoint", "", "record completed directories in this file so an interrupted scan can be resumed")
+	resume := flag.Bool("resume", false, "skip the directories recorded in the -checkpoint file")
+	quarantineDir := flag.String("quarantine", "", "record where blocked files would be moved below this directory")
+	move := flag.Bool("move", false, "actually move blocked files into the -quarantine directory")
+	hashBlocklist := flag.String("hash-blocklist", "", "block files whose SHA-256 hash is listed in this file")
+	statePath := flag.String("state", "", "compare files with the previous run recorded in this file and update it")
+	decisionLog := flag.String("decision-log", "", "write the allow or block decision on every file to this NDJSON file")
+	incrementalExport := flag.String("incremental-export", "", "append blocked files to this NDJSON file while scanning")
+	sftpKey := flag.String("sftp-key", "", "private key offered to sftp:// roots")
+	sftpKnownHosts := flag.String("sftp-known-hosts", "", "known_hosts file for sftp:// roots; defaults to ~/.ssh/known_hosts")
+	streamPath := flag.String("stream", "", "write results to this NDJSON file instead of keeping them in memory")
+	flag.Parse()
+
+	root := "./test-directory"
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
 	config := models.ScanConfig{
 		MaxFileSizeMB:       50,
 		ScanRecursively:     true,
@@ -16,10 +48,53 @@ func main() {
 		WorkerCount:         4,
 		BufferSize:          1000,
 	}
+	if *preset != "" {
+		presetConfig, ok := models.Preset(*preset)
+		if !ok {
+			log.Fatalf("Unknown preset %q, choose one of: %s", *preset, strings.Join(models.PresetNames(), ", "))
+		}
+		config = presetConfig
+		config.ExportBlockedToJSON = true
+		config.WorkerCount = 4
+		config.BufferSize = 1000
+	}
+	config.CheckpointPath = *checkpointPath
+	config.ResumeFromCheckpoint = *resume
+	config.QuarantineDir = *quarantineDir
+	config.HashBlocklistPath = *hashBlocklist
+	config.StatePath = *statePath
+	config.DecisionLogPath = *decisionLog
+	config.IncrementalExportPath = *incrementalExport
+	config.StreamToFile = *streamPath
+	config.SFTPKeyPath = *sftpKey
+	config.SFTPKnownHostsPath = *sftpKnownHosts
+	if *move {
+		dryRun := false
+		config.DryRun = &dryRun
+	}
 
 	scanner := scanner.New(config)
 
-	result, err := scanner.Scan("./test-directory")
+	var stopProgress func()
+	if *showProgress {
+		stopProgress = reportProgress(os.Stderr, scanner, 200*time.Millisecond)
+	}
+
+	var result *models.ScanResult
+	var err error
+	if *fromStdin {
+		var paths []string
+		paths, err = readPaths(os.Stdin)
+		if err != nil {
+			log.Fatalf("Error reading paths from stdin: %v", err)
+		}
+		result, err = scanner.ScanPaths(paths)
+	} else {
+		result, err = scanner.Scan(root)
+	}
+	if stopProgress != nil {
+		stopProgress()
+	}
 	if err != nil {
 		log.Fatalf("Error scanning directory: %v", err)
 	}
@@ -36,4 +111,57 @@ func main() {
 	fmt.Printf("Blocked Files: %d\n", result.Progress.BlockedFiles)
 	fmt.Printf("Total Size: %.2f MB\n", float64(result.Progress.TotalSize)/(1024*1024))
 	fmt.Printf("Duration: %v\n", result.Duration)
+
+	if *statsPath != "" {
+		if err := csvexport.ExportStatsCSV(result, *statsPath); err != nil {
+			log.Fatalf("Error writing statistics: %v", err)
+		}
+	}
+}
+
+// readPaths returns the non-empty lines of r, as produced by find or git ls-files
+func readPaths(r io.Reader) ([]string, error) {
+	var paths []string
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		if line := strings.TrimRight(lines.Text(), "\r"); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, lines.Err()
+}
+
+// reportProgress redraws a single status line on w until the returned stop
+// function is called. Stop erases the line so the final output starts clean.
+func reportProgress(w io.Writer, s *scanner.Scanner, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				fmt.Fprint(w, clearLine)
+				return
+			case <-ticker.C:
+				fmt.Fprint(w, "\r"+renderProgress(*s.GetProgress(), time.Now()))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// renderProgress formats a progress snapshot as a single status line
+func renderProgress(p models.ScanProgress, now time.Time) string {
+	elapsed := now.Sub(p.StartTime).Round(time.Second)
+	return fmt.Sprintf("Scanned %d/%d files, %d blocked, %v elapsed\033[K",
+		p.ScannedFiles, p.TotalFiles, p.BlockedFiles, elapsed)
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestRenderProgress(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	progress := models.ScanProgress{
+		TotalFiles:   120,
+		ScannedFiles: 42,
+		BlockedFiles: 3,
+		StartTime:    start,
+	}
+
+	line := renderProgress(progress, start.Add(90*time.Second))
+
+	for _, want := range []string{"42/120", "3 blocked", "1m30s"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected progress line to contain %q, got %q", want, line)
+		}
+	}
+	if strings.Contains(line, "\n") {
+		t.Errorf("Expected a single line, got %q", line)
+	}
+}
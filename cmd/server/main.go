@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
@@ -11,6 +13,10 @@ import (
 )
 
 func main() {
+	if n, err := strconv.Atoi(os.Getenv("SCAN_MAX_CONCURRENT")); err == nil {
+		api.SetMaxConcurrentScans(n)
+	}
+
 	router := mux.NewRouter()
 
 	// Static files
@@ -20,7 +26,11 @@ func main() {
 
 	// API routes
 	router.HandleFunc("/api/scan", api.StartScan).Methods("POST")
+	router.HandleFunc("/api/scan/stream", api.StreamScan).Methods("POST")
 	router.HandleFunc("/api/status", api.GetStatus).Methods("GET")
+	router.HandleFunc("/api/config/schema", api.GetConfigSchema).Methods("GET")
+	router.HandleFunc("/api/export", api.ExportScan).Methods("GET")
+	router.HandleFunc("/api/results", api.GetResults).Methods("GET")
 	router.HandleFunc("/api/ws", api.WebSocketHandler)
 
 	// Web routes
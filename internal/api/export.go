@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"filesystem-logger/internal/utils/csvexport"
+	"filesystem-logger/internal/utils/jsonexport"
+)
+
+func ExportScan(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "id parameter required", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "sizetree" {
+		http.Error(w, fmt.Sprintf("unsupported format %q", format), http.StatusBadRequest)
+		return
+	}
+
+	style, err := jsonexport.ParseKeyStyle(r.URL.Query().Get("keys"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	csvOptions, err := parseCSVOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, ok := GetResultByID(id)
+	if !ok {
+		http.Error(w, "scan not found", http.StatusNotFound)
+		return
+	}
+
+	filename := fmt.Sprintf("%s_blocked_files.%s", filepath.Base(id), format)
+	if format == "sizetree" {
+		filename = fmt.Sprintf("%s_size_tree.json", filepath.Base(id))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		err = csvexport.WriteBlockedFilesWithOptions(result, w, csvOptions)
+	case "sizetree":
+		w.Header().Set("Content-Type", "application/json")
+		err = jsonexport.WriteSizeTree(result, w)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		err = jsonexport.WriteBlockedFilesWithStyle(result, w, style)
+	}
+	if err != nil {
+		log.Printf("export of %s failed: %v", id, err)
+	}
+}
+
+// parseCSVOptions reads the lineEnding (lf or crlf) and bom parameters of a
+// CSV export
+func parseCSVOptions(query url.Values) (csvexport.Options, error) {
+	var opts csvexport.Options
+	switch ending := query.Get("lineEnding"); strings.ToLower(ending) {
+	case "", "lf":
+	case "crlf":
+		opts.CRLF = true
+	default:
+		return opts, fmt.Errorf("unsupported line ending %q", ending)
+	}
+	if bom := query.Get("bom"); bom != "" {
+		value, err := strconv.ParseBool(bom)
+		if err != nil {
+			return opts, fmt.Errorf("invalid bom value %q", bom)
+		}
+		opts.BOM = value
+	}
+	return opts, nil
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestExportScan(t *testing.T) {
+	scanMutex.Lock()
+	scanResults["/data"] = &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/data/big.iso", Name: "big.iso", Size: 4096, IsBlocked: true, BlockReason: "File size exceeds limit"},
+			{Path: "/data/small.txt", Name: "small.txt", Size: 10},
+		},
+	}
+	scanMutex.Unlock()
+	defer func() {
+		scanMutex.Lock()
+		delete(scanResults, "/data")
+		scanMutex.Unlock()
+	}()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedType   string
+	}{
+		{name: "JSON export", query: "id=/data&format=json", expectedStatus: http.StatusOK, expectedType: "application/json"},
+		{name: "CSV export", query: "id=/data&format=csv", expectedStatus: http.StatusOK, expectedType: "text/csv"},
+		{name: "Unknown scan", query: "id=/missing&format=json", expectedStatus: http.StatusNotFound},
+		{name: "Unsupported format", query: "id=/data&format=xml", expectedStatus: http.StatusBadRequest},
+		{name: "Unknown key style", query: "id=/data&keys=kebab", expectedStatus: http.StatusBadRequest},
+		{name: "Unknown line ending", query: "id=/data&format=csv&lineEnding=cr", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid BOM flag", query: "id=/data&format=csv&bom=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/export?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			ExportScan(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != tt.expectedType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.expectedType, got)
+			}
+			if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, "attachment") {
+				t.Errorf("Expected attachment disposition, got %q", got)
+			}
+
+			switch tt.expectedType {
+			case "application/json":
+				var exported struct {
+					BlockedCount int64 `json:"blockedCount"`
+				}
+				if err := json.NewDecoder(rec.Body).Decode(&exported); err != nil {
+					t.Fatalf("Failed to decode JSON export: %v", err)
+				}
+				if exported.BlockedCount != 1 {
+					t.Errorf("Expected blockedCount=1, got %d", exported.BlockedCount)
+				}
+			case "text/csv":
+				records, err := csv.NewReader(rec.Body).ReadAll()
+				if err != nil {
+					t.Fatalf("Failed to parse CSV export: %v", err)
+				}
+				if len(records) != 2 || records[1][1] != "big.iso" {
+					t.Errorf("Unexpected CSV export: %v", records)
+				}
+			}
+		})
+	}
+}
+
+func TestExportScanKeyStyle(t *testing.T) {
+	scanMutex.Lock()
+	scanResults["/data"] = &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/data/big.iso", Name: "big.iso", Size: 4096, IsBlocked: true, BlockReason: "File size exceeds limit"},
+		},
+	}
+	scanMutex.Unlock()
+	defer func() {
+		scanMutex.Lock()
+		delete(scanResults, "/data")
+		scanMutex.Unlock()
+	}()
+
+	req := httptest.NewRequest("GET", "/api/export?id=/data&keys=snake", nil)
+	rec := httptest.NewRecorder()
+
+	ExportScan(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	body := rec.Body.String()
+	for _, key := range []string{`"blocked_count"`, `"block_reason"`} {
+		if !strings.Contains(body, key) {
+			t.Errorf("Expected key %s in %s", key, body)
+		}
+	}
+}
+
+func TestExportScanSizeTree(t *testing.T) {
+	scanMutex.Lock()
+	scanResults["/data"] = &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/data", Name: "data", IsDirectory: true},
+			{Path: "/data/big.iso", Name: "big.iso", Size: 4096, IsBlocked: true},
+			{Path: "/data/small.txt", Name: "small.txt", Size: 10},
+		},
+	}
+	scanMutex.Unlock()
+	defer func() {
+		scanMutex.Lock()
+		delete(scanResults, "/data")
+		scanMutex.Unlock()
+	}()
+
+	req := httptest.NewRequest("GET", "/api/export?id=/data&format=sizetree", nil)
+	rec := httptest.NewRecorder()
+
+	ExportScan(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var tree struct {
+		Name     string            `json:"name"`
+		Value    int64             `json:"value"`
+		Children []json.RawMessage `json:"children"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&tree); err != nil {
+		t.Fatalf("Failed to decode size tree: %v", err)
+	}
+	if tree.Name != "data" || tree.Value != 4106 || len(tree.Children) != 2 {
+		t.Errorf("Expected a data root of 4106 bytes with 2 children, got %+v", tree)
+	}
+}
+
+func TestExportScanCSVOptions(t *testing.T) {
+	scanMutex.Lock()
+	scanResults["/data"] = &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/data/big.iso", Name: "big.iso", Size: 4096, IsBlocked: true, BlockReason: "File size exceeds limit"},
+		},
+	}
+	scanMutex.Unlock()
+	defer func() {
+		scanMutex.Lock()
+		delete(scanResults, "/data")
+		scanMutex.Unlock()
+	}()
+
+	tests := []struct {
+		name  string
+		query string
+		crlf  bool
+		bom   bool
+	}{
+		{name: "Defaults", query: "id=/data&format=csv"},
+		{name: "CRLF", query: "id=/data&format=csv&lineEnding=crlf", crlf: true},
+		{name: "BOM", query: "id=/data&format=csv&bom=true", bom: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/export?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			ExportScan(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+			body := rec.Body.String()
+			if got := strings.Contains(body, "\r\n"); got != tt.crlf {
+				t.Errorf("Expected CRLF line endings %v, got %q", tt.crlf, body)
+			}
+			if got := strings.HasPrefix(body, "\ufeff"); got != tt.bom {
+				t.Errorf("Expected a BOM %v, got %q", tt.bom, body)
+			}
+		})
+	}
+}
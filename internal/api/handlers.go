@@ -2,26 +2,51 @@ package api
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"filesystem-logger/internal/models"
 	"filesystem-logger/internal/scanner"
-
-	"github.com/gorilla/websocket"
 )
 
 var (
 	activeScans = make(map[string]*scanner.Scanner)
 	scanResults = make(map[string]*models.ScanResult)
+	queuedScans = make(map[string]*scanner.Scanner)
 	scanMutex   sync.RWMutex
 )
 
+// newScanner creates the scanner for each job started through StartScan
+var newScanner = scanner.New
+
+// defaultMaxConcurrentScans is the number of scans that run at once unless
+// SetMaxConcurrentScans says otherwise
+const defaultMaxConcurrentScans = 2
+
+// scanSlots bounds the number of scans that run at the same time, whether
+// started through StartScan, StreamScan or the WebSocket; further requests
+// wait, those from StartScan in the "queued" state
+var scanSlots = make(chan struct{}, defaultMaxConcurrentScans)
+
+// SetMaxConcurrentScans changes how many scans may run at once. It must be
+// called before the server starts handling requests.
+func SetMaxConcurrentScans(n int) {
+	if n <= 0 {
+		n = defaultMaxConcurrentScans
+	}
+	scanSlots = make(chan struct{}, n)
+}
+
 func StartScan(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Path   string            `json:"path"`
-		Config models.ScanConfig `json:"config"`
+		Path   string          `json:"path"`
+		Preset string          `json:"preset"`
+		Config json.RawMessage `json:"config"`
+		Force  bool            `json:"force"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -34,31 +59,133 @@ func StartScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s := scanner.New(req.Config)
+	config, err := resolveConfig(req.Preset, req.Config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Start scan in goroutine
-	go func() {
-		result, err := s.Scan(req.Path)
-		if err != nil {
-			scanMutex.Lock()
-			activeScans[req.Path] = nil
-			scanMutex.Unlock()
-			return
+	// Scans are identified by their resolved path, so a retried request
+	// for the same directory joins the scan that is already queued or
+	// running instead of starting another one, unless it is forced
+	id := resolveScanPath(req.Path)
+	scanMutex.Lock()
+	s, running := activeScans[id]
+	running = running && s != nil
+	if !req.Force && (queuedScans[id] != nil || running) {
+		state := "running"
+		if queuedScans[id] != nil {
+			state = "queued"
 		}
-		// Store both scanner and result
-		scanMutex.Lock()
-		scanResults[req.Path] = result
-		activeScans[req.Path] = s
 		scanMutex.Unlock()
-	}()
+		json.NewEncoder(w).Encode(map[string]string{
+			"status": state,
+			"id":     id,
+			"path":   req.Path,
+		})
+		return
+	}
+	// A forced scan replaces the queued or running one, whose result would
+	// be discarded anyway
+	if running {
+		s.Cancel()
+	}
+	job := newScanner(config)
+	queuedScans[id] = job
+	delete(activeScans, id)
+	scanMutex.Unlock()
+
+	go runScanJob(job, id, scanSlots)
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{
-		"status": "started",
+		"status": "queued",
+		"id":     id,
 		"path":   req.Path,
 	})
 }
 
+// resolveScanPath returns the absolute path with symlinks evaluated that
+// identifies a scan, falling back to the path as given. Remote roots such
+// as sftp://host/path are used as given.
+func resolveScanPath(path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
+
+// runScanJob waits for a free slot and then runs the scan, moving the job
+// from queued to running to completed or error
+func runScanJob(s *scanner.Scanner, path string, slots chan struct{}) {
+	slots <- struct{}{}
+	defer func() { <-slots }()
+
+	scanMutex.Lock()
+	if queuedScans[path] != s {
+		// Replaced by a forced scan while waiting
+		scanMutex.Unlock()
+		return
+	}
+	delete(queuedScans, path)
+	activeScans[path] = s
+	scanMutex.Unlock()
+
+	result, err := s.Scan(path)
+
+	scanMutex.Lock()
+	defer scanMutex.Unlock()
+	// A forced scan of the same path took over
+	if activeScans[path] != s {
+		return
+	}
+	if err != nil {
+		activeScans[path] = nil
+		return
+	}
+	delete(activeScans, path)
+	scanResults[path] = result
+}
+
+// resolveConfig starts from the named preset, if any, and applies the
+// fields present in raw on top of it
+func resolveConfig(preset string, raw json.RawMessage) (models.ScanConfig, error) {
+	var config models.ScanConfig
+	if preset != "" {
+		var ok bool
+		if config, ok = models.Preset(preset); !ok {
+			return config, fmt.Errorf("unknown preset %q", preset)
+		}
+	}
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return config, fmt.Errorf("invalid config: %v", err)
+		}
+	}
+
+	return config, nil
+}
+
+// scanErrorStatus maps an error returned by Scan to an HTTP status code
+func scanErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, scanner.ErrRootNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scanner.ErrRootNotDirectory), errors.Is(err, scanner.ErrUnsupportedRoot):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 func GetStatus(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Query().Get("id")
 	if path == "" {
@@ -67,6 +194,7 @@ func GetStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	scanMutex.RLock()
+	queued := queuedScans[path] != nil
 	scanner, scannerExists := activeScans[path]
 	result, resultExists := scanResults[path]
 	scanMutex.RUnlock()
@@ -79,7 +207,9 @@ func GetStatus(w http.ResponseWriter, r *http.Request) {
 		State: "unknown",
 	}
 
-	if !scannerExists {
+	if queued {
+		status.State = "queued"
+	} else if !scannerExists {
 		if resultExists {
 			status.State = "completed"
 			status.Result = result
@@ -96,22 +226,3 @@ func GetStatus(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(status)
 }
-
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // In production, check origin
-	},
-}
-
-func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	defer conn.Close()
-
-	// Handle real-time updates
-}
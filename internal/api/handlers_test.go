@@ -7,6 +7,9 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -102,8 +105,8 @@ func TestStartScan(t *testing.T) {
 
 			// Check response content
 			if !tt.expectedError {
-				if status, ok := response["status"].(string); !ok || status != "started" {
-					t.Errorf("Expected status 'started', got %v", status)
+				if status, ok := response["status"].(string); !ok || status != "queued" {
+					t.Errorf("Expected status 'queued', got %v", status)
 				}
 
 				// Wait for scan to complete
@@ -192,6 +195,147 @@ func TestGetStatus(t *testing.T) {
 	scanMutex.Unlock()
 }
 
+func TestScanQueue(t *testing.T) {
+	// Eén slot, dat de test zelf bezet houdt
+	defer func(slots chan struct{}) { scanSlots = slots }(scanSlots)
+	SetMaxConcurrentScans(1)
+	scanSlots <- struct{}{}
+
+	dirs := []string{setupTestData(t), setupTestData(t)}
+	for _, dir := range dirs {
+		body, _ := json.Marshal(map[string]interface{}{"path": dir})
+		rec := httptest.NewRecorder()
+		StartScan(rec, httptest.NewRequest("POST", "/api/scan", bytes.NewBuffer(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	}
+
+	stateOf := func(id string) string {
+		rec := httptest.NewRecorder()
+		GetStatus(rec, httptest.NewRequest("GET", "/api/status?id="+id, nil))
+		var response map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&response)
+		state, _ := response["state"].(string)
+		return state
+	}
+
+	for _, dir := range dirs {
+		if state := stateOf(dir); state != "queued" {
+			t.Errorf("Expected state queued while no slot is free, got %s", state)
+		}
+	}
+
+	t.Run("Duplicate request", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"path": dirs[0]})
+		rec := httptest.NewRecorder()
+		StartScan(rec, httptest.NewRequest("POST", "/api/scan", bytes.NewBuffer(body)))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var response map[string]string
+		json.NewDecoder(rec.Body).Decode(&response)
+		if response["status"] != "queued" || response["id"] != dirs[0] {
+			t.Errorf("Expected the queued scan of %s to be returned, got %v", dirs[0], response)
+		}
+	})
+
+	// Geef het slot vrij zodat de scans na elkaar draaien
+	<-scanSlots
+	deadline := time.Now().Add(5 * time.Second)
+	for _, dir := range dirs {
+		for stateOf(dir) != "completed" {
+			if time.Now().After(deadline) {
+				t.Fatalf("Scan of %s did not complete, state %s", dir, stateOf(dir))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	scanMutex.Lock()
+	scanResults = make(map[string]*models.ScanResult)
+	scanMutex.Unlock()
+}
+
+func TestStartScanIdempotent(t *testing.T) {
+	// Houd het enige slot bezet zodat de scan blijft wachten
+	defer func(slots chan struct{}) { scanSlots = slots }(scanSlots)
+	SetMaxConcurrentScans(1)
+	scanSlots <- struct{}{}
+
+	var created int32
+	defer func(create func(models.ScanConfig) *scanner.Scanner) { newScanner = create }(newScanner)
+	newScanner = func(config models.ScanConfig) *scanner.Scanner {
+		atomic.AddInt32(&created, 1)
+		return scanner.New(config)
+	}
+
+	dir := setupTestData(t)
+	start := func(body map[string]interface{}) map[string]string {
+		data, _ := json.Marshal(body)
+		rec := httptest.NewRecorder()
+		StartScan(rec, httptest.NewRequest("POST", "/api/scan", bytes.NewBuffer(data)))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var response map[string]string
+		json.NewDecoder(rec.Body).Decode(&response)
+		return response
+	}
+
+	var wg sync.WaitGroup
+	responses := make([]map[string]string, 2)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Het tweede verzoek gebruikt een niet-opgeschoond pad naar dezelfde map
+			path := dir
+			if i == 1 {
+				path = filepath.Join(dir, "subdir", "..")
+			}
+			responses[i] = start(map[string]interface{}{"path": path})
+		}(i)
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&created); n != 1 {
+		t.Errorf("Expected 1 scanner to be created, got %d", n)
+	}
+	if responses[0]["id"] != responses[1]["id"] {
+		t.Errorf("Expected both requests to return the same id, got %s and %s", responses[0]["id"], responses[1]["id"])
+	}
+
+	t.Run("Forced request", func(t *testing.T) {
+		start(map[string]interface{}{"path": dir, "force": true})
+		if n := atomic.LoadInt32(&created); n != 2 {
+			t.Errorf("Expected a forced request to create a second scanner, got %d", n)
+		}
+	})
+
+	<-scanSlots
+	id := responses[0]["id"]
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		scanMutex.RLock()
+		_, done := scanResults[id]
+		queued := queuedScans[id] != nil
+		_, running := activeScans[id]
+		scanMutex.RUnlock()
+		if done && !queued && !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Scan of %s did not complete", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	scanMutex.Lock()
+	scanResults = make(map[string]*models.ScanResult)
+	scanMutex.Unlock()
+}
+
 func TestWebSocketHandler(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(WebSocketHandler))
@@ -213,6 +357,70 @@ func TestWebSocketHandler(t *testing.T) {
 }
 
 // Helper function to create test files and directories
+func TestResolveConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		preset        string
+		raw           string
+		expectedError bool
+		expectedSize  int
+		expectedTypes int
+	}{
+		{name: "No preset", raw: `{"maxFileSizeMB": 10}`, expectedSize: 10},
+		{name: "Preset only", preset: "images", expectedSize: 100, expectedTypes: 9},
+		{name: "Explicit field overrides preset", preset: "images", raw: `{"maxFileSizeMB": 10}`, expectedSize: 10, expectedTypes: 9},
+		{name: "Explicit slice replaces preset", preset: "images", raw: `{"allowedTypes": [".png"]}`, expectedSize: 100, expectedTypes: 1},
+		{name: "Unknown preset", preset: "bogus", expectedError: true},
+		{name: "Invalid config", raw: `{"maxFileSizeMB": "ten"}`, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := resolveConfig(tt.preset, json.RawMessage(tt.raw))
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if config.MaxFileSizeMB != tt.expectedSize {
+				t.Errorf("Expected MaxFileSizeMB %d, got %d", tt.expectedSize, config.MaxFileSizeMB)
+			}
+			if len(config.AllowedTypes) != tt.expectedTypes {
+				t.Errorf("Expected %d allowed types, got %v", tt.expectedTypes, config.AllowedTypes)
+			}
+		})
+	}
+}
+
+func TestResolveConfigIgnoresServerFiles(t *testing.T) {
+	// Velden die bestanden op de server schrijven of verwijderen
+	raw := `{
+		"checkpointPath": "/etc/passwd",
+		"resumeFromCheckpoint": true,
+		"quarantineDir": "/tmp",
+		"dryRun": false,
+		"hashBlocklistPath": "/etc/shadow",
+		"decisionLogPath": "/etc/hosts",
+		"statePath": "/etc/hosts",
+		"incrementalExportPath": "/etc/hosts",
+		"streamToFile": "/etc/hosts",
+		"streamCRLF": true,
+		"sftpKeyPath": "/root/.ssh/id_ed25519",
+		"sftpKnownHostsPath": "/etc/hosts"
+	}`
+	config, err := resolveConfig("", json.RawMessage(raw))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(config, models.ScanConfig{}) {
+		t.Errorf("Expected server file fields to be ignored, got %+v", config)
+	}
+}
+
 func setupTestData(t *testing.T) string {
 	t.Helper()
 
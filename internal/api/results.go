@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"filesystem-logger/internal/models"
+)
+
+// Page sizes of GetResults
+const (
+	defaultResultsLimit = 100
+	maxResultsLimit     = 1000
+)
+
+// GetResultByID returns the result of the completed scan with the given id.
+// It is safe to call while scans are running.
+func GetResultByID(id string) (*models.ScanResult, bool) {
+	scanMutex.RLock()
+	defer scanMutex.RUnlock()
+
+	result, ok := scanResults[id]
+	return result, ok && result != nil
+}
+
+// GetProgressByID returns a snapshot of the progress of the running scan
+// with the given id
+func GetProgressByID(id string) (*models.ScanProgress, bool) {
+	scanMutex.RLock()
+	s := activeScans[id]
+	scanMutex.RUnlock()
+
+	if s == nil {
+		return nil, false
+	}
+	return s.GetProgress(), true
+}
+
+// GetResults serves one page of the files of a completed scan. The optional
+// category parameter keeps only files of that category, such as "image",
+// before offset and limit are applied.
+func GetResults(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := query.Get("id")
+	if id == "" {
+		http.Error(w, "id parameter required", http.StatusBadRequest)
+		return
+	}
+
+	offset, err := queryInt(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		http.Error(w, fmt.Sprintf("invalid offset %q", query.Get("offset")), http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(query.Get("limit"), defaultResultsLimit)
+	if err != nil || limit <= 0 {
+		http.Error(w, fmt.Sprintf("invalid limit %q", query.Get("limit")), http.StatusBadRequest)
+		return
+	}
+	if limit > maxResultsLimit {
+		limit = maxResultsLimit
+	}
+
+	result, ok := GetResultByID(id)
+	if !ok {
+		http.Error(w, "scan not found", http.StatusNotFound)
+		return
+	}
+
+	files := result.Files
+	if category := query.Get("category"); category != "" {
+		files = result.Filter(func(file models.FileInfo) bool {
+			return strings.EqualFold(file.Category, category)
+		}).Files
+	}
+
+	page := struct {
+		Total  int               `json:"total"`
+		Offset int               `json:"offset"`
+		Limit  int               `json:"limit"`
+		Files  []models.FileInfo `json:"files"`
+	}{
+		Total:  len(files),
+		Offset: offset,
+		Limit:  limit,
+		Files:  []models.FileInfo{},
+	}
+	if offset < len(files) {
+		end := offset + limit
+		if end > len(files) {
+			end = len(files)
+		}
+		page.Files = files[offset:end]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// queryInt parses an integer query parameter, returning def when it is empty
+func queryInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
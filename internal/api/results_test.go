@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"filesystem-logger/internal/models"
+	"filesystem-logger/internal/scanner"
+)
+
+func TestGetResultByID(t *testing.T) {
+	stored := &models.ScanResult{Root: "/data", Success: true}
+
+	scanMutex.Lock()
+	scanResults["/data"] = stored
+	activeScans["/running"] = scanner.New(models.ScanConfig{})
+	activeScans["/failed"] = nil
+	scanMutex.Unlock()
+	defer func() {
+		scanMutex.Lock()
+		delete(scanResults, "/data")
+		delete(activeScans, "/running")
+		delete(activeScans, "/failed")
+		scanMutex.Unlock()
+	}()
+
+	if result, ok := GetResultByID("/data"); !ok || result != stored {
+		t.Errorf("Expected the stored result, got %v, %v", result, ok)
+	}
+	if _, ok := GetResultByID("/missing"); ok {
+		t.Error("Expected no result for an unknown id")
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		expected bool
+	}{
+		{name: "Running scan", id: "/running", expected: true},
+		{name: "Failed scan", id: "/failed", expected: false},
+		{name: "Unknown scan", id: "/missing", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress, ok := GetProgressByID(tt.id)
+			if ok != tt.expected || (ok && progress == nil) {
+				t.Errorf("Expected progress found=%v, got %v (%v)", tt.expected, ok, progress)
+			}
+		})
+	}
+}
+
+func TestGetResults(t *testing.T) {
+	scanMutex.Lock()
+	scanResults["/photos"] = &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/photos", Name: "photos", IsDirectory: true},
+			{Path: "/photos/a.jpg", Name: "a.jpg", Category: "image"},
+			{Path: "/photos/notes.txt", Name: "notes.txt", Category: "document"},
+			{Path: "/photos/b.png", Name: "b.png", Category: "image"},
+			{Path: "/photos/clip.mp4", Name: "clip.mp4", Category: "video"},
+			{Path: "/photos/c.gif", Name: "c.gif", Category: "image"},
+		},
+	}
+	scanMutex.Unlock()
+	defer func() {
+		scanMutex.Lock()
+		delete(scanResults, "/photos")
+		scanMutex.Unlock()
+	}()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedTotal  int
+		expectedNames  []string
+	}{
+		{name: "All files", query: "id=/photos", expectedStatus: http.StatusOK, expectedTotal: 6,
+			expectedNames: []string{"photos", "a.jpg", "notes.txt", "b.png", "clip.mp4", "c.gif"}},
+		{name: "Images", query: "id=/photos&category=image", expectedStatus: http.StatusOK, expectedTotal: 3,
+			expectedNames: []string{"a.jpg", "b.png", "c.gif"}},
+		{name: "Images paginated", query: "id=/photos&category=image&offset=1&limit=1", expectedStatus: http.StatusOK, expectedTotal: 3,
+			expectedNames: []string{"b.png"}},
+		{name: "Offset past the end", query: "id=/photos&category=image&offset=5", expectedStatus: http.StatusOK, expectedTotal: 3,
+			expectedNames: []string{}},
+		{name: "Unknown category", query: "id=/photos&category=archive", expectedStatus: http.StatusOK, expectedTotal: 0,
+			expectedNames: []string{}},
+		{name: "Invalid limit", query: "id=/photos&limit=-1", expectedStatus: http.StatusBadRequest},
+		{name: "Unknown scan", query: "id=/missing", expectedStatus: http.StatusNotFound},
+		{name: "Missing id", query: "category=image", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/results?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			GetResults(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var page struct {
+				Total int               `json:"total"`
+				Files []models.FileInfo `json:"files"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if page.Total != tt.expectedTotal {
+				t.Errorf("Expected total %d, got %d", tt.expectedTotal, page.Total)
+			}
+			names := []string{}
+			for _, file := range page.Files {
+				names = append(names, file.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expectedNames) {
+				t.Errorf("Expected files %v, got %v", tt.expectedNames, names)
+			}
+		})
+	}
+}
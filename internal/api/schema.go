@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"filesystem-logger/internal/models"
+)
+
+// ConfigField describes a single ScanConfig field for dynamic forms
+type ConfigField struct {
+	Name        string      `json:"name"`
+	JSONName    string      `json:"json"`
+	Type        string      `json:"type"`
+	Items       string      `json:"items,omitempty"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description"`
+}
+
+// ExportParam describes a query parameter of the export endpoint
+type ExportParam struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description"`
+}
+
+// exportParams documents the query parameters ExportScan accepts
+var exportParams = []ExportParam{
+	{"id", "string", "", "Path of the completed scan to export"},
+	{"format", "string", "json", "Export format: json, csv or sizetree"},
+	{"keys", "string", "camel", "Key style of the JSON export: camel or snake"},
+	{"lineEnding", "string", "lf", "Line ending of the CSV export: lf or crlf, for Windows consumers"},
+	{"bom", "boolean", false, "Prepend a UTF-8 byte order mark to the CSV export so Excel detects the encoding"},
+}
+
+type fieldDoc struct {
+	description  string
+	defaultValue interface{}
+}
+
+// configFieldDocs annotates ScanConfig fields by their json name
+var configFieldDocs = map[string]fieldDoc{
+	"maxFileSizeMB":           {"Files larger than this size in megabytes are blocked", 0},
+	"allowedTypes":            {"File extensions that are allowed, with or without the leading dot; all other types are blocked when set", nil},
+	"blockedPatterns":         {"Glob patterns matched against file names that cause a file to be blocked", nil},
+	"scanRecursively":         {"Descend into subdirectories", false},
+	"exportBlockedToJSON":     {"Write blocked files to blocked_files.json in the scanned directory", false},
+	"workerCount":             {"Number of concurrent workers", 4},
+	"bufferSize":              {"Channel buffer size", 1000},
+	"workBufferSize":          {"Work queue buffer size; defaults to bufferSize", 0},
+	"resultBufferSize":        {"Result queue buffer size; defaults to bufferSize", 0},
+	"maxOpenFiles":            {"Maximum number of files held open at once; 0 means no limit", 0},
+	"maxReadBytesPerSecond":   {"Limit on bytes read per second across all workers; 0 means unlimited", 0},
+	"maxReadBytes":            {"Stop opening files once this many bytes were read in total; later files are recorded from metadata only. 0 means no limit", 0},
+	"ioConcurrency":           {"Maximum number of workers reading file contents at once; use 1 on spinning disks, 0 means no limit", 0},
+	"flagExtensionMismatch":   {"Flag files whose content does not match their extension", false},
+	"blockExtensionMismatch":  {"Block files flagged with an extension mismatch", false},
+	"computeEntropy":          {"Estimate the Shannon entropy of each file from its first 512 bytes", false},
+	"omitBlocked":             {"Leave blocked files out of the results while still counting and exporting them", false},
+	"detectHardLinks":         {"Group hard-linked files by device and inode and count their size once (Unix only)", false},
+	"maxPathLength":           {"Files whose full path is longer than this many characters are blocked; 0 means no limit", 0},
+	"maxNameLength":           {"Files whose name, without the directory, is longer than this many characters are blocked; 0 means no limit", 0},
+	"maxDirEntries":           {"Directories with more entries than this are reported but not descended into; 0 means no limit", 0},
+	"previewBytes":            {"Keep up to this many opening bytes of text files as a preview; 0 disables previews", 0},
+	"caseInsensitivePatterns": {"Match blocked patterns and allowlist globs ignoring case", false},
+	"computeHash":             {"Compute the SHA-256 hash of each file's content", false},
+	"quickHash":               {"Hash only the size plus the first and last 64 KB of each file; fast, but files differing only in the middle collide", false},
+	"failOnError":             {"Return an error from the scan when any file or directory could not be read", false},
+	"blockSuspiciousNames":    {"Block files whose names contain control characters, such as newlines, or path separators", false},
+	"reportSlowestFiles":      {"Report this many files that took longest to process, slowest first; 0 disables timing", 0},
+	"collectWorkerStats":      {"Report how many files and bytes each worker processed", false},
+	"pruneEmptyDirs":          {"Drop directories without any file in the results below them; has no effect when results are streamed to a file", false},
+	"followSymlinkDirs":       {"Descend into symlinked directories; loops back into the walk path are detected and skipped", false},
+	"followSymlinkFiles":      {"Scan the targets of symlinked files instead of reporting the links themselves", false},
+	"relativePaths":           {"Record paths relative to the scan root, which is stored separately in the result, so results and exports are portable", false},
+	"blockEmptyFiles":         {"Block zero-length files", false},
+	"sortResults":             {"Order result files by path so repeated scans produce identical output", false},
+	"flagWorldWritable":       {"Flag files that anyone may write to (Unix only)", false},
+	"blockWorldWritable":      {"Block files flagged as world-writable", false},
+	"aggregateDirSizes":       {"Report the total size of the files below each directory as its size; has no effect when results are streamed to a file", false},
+	"resolveRealPaths":        {"Record the path of each file with every symlink resolved as realPath, for keying on canonical paths", false},
+	"maxPerExtension":         {"Only process this many files per extension and count the rest as sampled out; 0 means no limit. Sampled out files still count towards totalFiles; use sequential traversal for a repeatable sample", 0},
+	"preferContentType":       {"Derive fileType from the detected content instead of the extension when they disagree", false},
+	"requireDirRoot":          {"Fail the scan when the root is a file instead of a directory", false},
+	"progressInterval":        {"Minimum time between progress updates in nanoseconds; 0 means 250ms", 0},
+	"traversalMode":           {"parallel walks directories concurrently with a worker pool; sequential walks depth-first in lexical order on one goroutine", "parallel"},
+	"exportKeyStyle":          {"Key casing of blocked_files.json: camel (blockReason) or snake (block_reason)", "camel"},
+	"cancelTimeout":           {"Maximum time in nanoseconds a cancelled scan waits for workers stuck in slow I/O before returning a partial result; 0 means 5s", 0},
+	"hashAlgorithm":           {"Algorithm for computeHash and quickHash: sha256, sha1, md5 or blake3", "sha256"},
+	"textExtensions":          {"Extensions whose files are always previewed and searched for keywords as text, even when their content is detected as binary", nil},
+	"keywords":                {"Words searched for, ignoring case, in the content of text files; the ones found are listed in matchedKeywords", nil},
+	"ruleGroups":              {"Named groups of blockedPatterns; files matching a group are blocked and the group is listed in matchedRuleGroups", nil},
+	"evaluateAllRuleGroups":   {"Check every rule group, even for files already blocked, so matchedRuleGroups lists all matching groups", false},
+	"trustExtensionFor":       {"Extensions whose MIME type is taken from the extension; their files are not opened for detection", nil},
+	"allowlistPaths":          {"Globs matched against the path relative to the scan root; matching files are never blocked", nil},
+	"fileTypeNames":           {"Map of extensions, including compound ones like .tar.gz, to friendly file type names", nil},
+	"categories":              {"Map of extensions to categories, overriding the built-in image, video, document, archive, code and other buckets", nil},
+}
+
+// ConfigSchema describes the exported ScanConfig fields
+func ConfigSchema() []ConfigField {
+	t := reflect.TypeOf(models.ScanConfig{})
+
+	var fields []ConfigField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+		if jsonName == "-" || jsonName == "" {
+			continue
+		}
+
+		doc := configFieldDocs[jsonName]
+		field := ConfigField{
+			Name:        f.Name,
+			JSONName:    jsonName,
+			Type:        schemaType(f.Type),
+			Default:     doc.defaultValue,
+			Description: doc.description,
+		}
+		if f.Type.Kind() == reflect.Slice {
+			field.Items = schemaType(f.Type.Elem())
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func schemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.Kind().String()
+	}
+}
+
+func GetConfigSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"fields":       ConfigSchema(),
+		"exportParams": exportParams,
+	})
+}
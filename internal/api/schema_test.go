@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetConfigSchema(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/config/schema", nil)
+	rec := httptest.NewRecorder()
+
+	GetConfigSchema(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var response struct {
+		Fields       []ConfigField `json:"fields"`
+		ExportParams []ExportParam `json:"exportParams"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	found := false
+	for _, field := range response.Fields {
+		if field.JSONName == "maxFileSizeMB" {
+			found = true
+			if field.Type != "integer" {
+				t.Errorf("Expected maxFileSizeMB to have type integer, got %s", field.Type)
+			}
+		}
+		if field.Description == "" {
+			t.Errorf("Expected a description for field %s", field.JSONName)
+		}
+	}
+	if !found {
+		t.Error("Schema does not include maxFileSizeMB")
+	}
+
+	params := make(map[string]bool)
+	for _, param := range response.ExportParams {
+		params[param.Name] = true
+	}
+	for _, name := range []string{"format", "lineEnding", "bom"} {
+		if !params[name] {
+			t.Errorf("Expected export parameter %s in the schema, got %v", name, response.ExportParams)
+		}
+	}
+}
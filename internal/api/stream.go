@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"filesystem-logger/internal/models"
+	"filesystem-logger/internal/scanner"
+)
+
+// streamSummary is the last NDJSON line of a streamed scan
+type streamSummary struct {
+	Summary struct {
+		Progress models.ScanProgress `json:"progress"`
+		Duration time.Duration       `json:"duration"`
+		Success  bool                `json:"success"`
+		Error    string              `json:"error,omitempty"`
+	} `json:"summary"`
+}
+
+// StreamScan runs a scan within the request and streams every file as an
+// NDJSON line while it is found, followed by a line holding the summary.
+// The scan is cancelled when the client goes away or the request deadline
+// passes.
+func StreamScan(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path   string          `json:"path"`
+		Preset string          `json:"preset"`
+		Config json.RawMessage `json:"config"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Path == "" {
+		http.Error(w, "path cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	config, err := resolveConfig(req.Preset, req.Config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Streamed scans count against the same limit as queued ones
+	ctx := r.Context()
+	select {
+	case scanSlots <- struct{}{}:
+		defer func() { <-scanSlots }()
+	case <-ctx.Done():
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	started := false
+
+	// OnFile runs on the collector goroutine while Scan blocks below, so
+	// the response is never written from two goroutines at once
+	config.OnFile = func(file models.FileInfo) {
+		started = true
+		if err := encoder.Encode(file); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	s := scanner.New(config)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Cancel()
+		case <-done:
+		}
+	}()
+
+	result, err := s.Scan(req.Path)
+	if err != nil && result == nil && !started {
+		// Nothing was streamed yet, so the failure can be a plain error
+		http.Error(w, err.Error(), scanErrorStatus(err))
+		return
+	}
+
+	var summary streamSummary
+	if result != nil {
+		summary.Summary.Progress = result.Progress
+		summary.Summary.Duration = result.Duration
+		summary.Summary.Success = result.Success
+	}
+	if err != nil {
+		summary.Summary.Success = false
+		summary.Summary.Error = err.Error()
+	}
+	if err := encoder.Encode(summary); err != nil {
+		log.Printf("streamed scan of %s: %v", req.Path, err)
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestStreamScan(t *testing.T) {
+	testDir := setupTestData(t)
+
+	t.Run("Streams files and summary", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"path":   testDir,
+			"config": models.ScanConfig{MaxFileSizeMB: 50, ScanRecursively: true},
+		})
+		req := httptest.NewRequest("POST", "/api/scan/stream", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		StreamScan(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("Expected NDJSON content type, got %q", ct)
+		}
+
+		var lines []string
+		scanner := bufio.NewScanner(rec.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) == 0 {
+			t.Fatal("Expected streamed lines")
+		}
+
+		files := lines[:len(lines)-1]
+		if len(files) != 5 { // root dir + subdir + 3 files
+			t.Errorf("Expected 5 streamed files, got %d", len(files))
+		}
+		for _, line := range files {
+			var file models.FileInfo
+			if err := json.Unmarshal([]byte(line), &file); err != nil || file.Path == "" {
+				t.Errorf("Expected a file record, got %s", line)
+			}
+		}
+
+		var summary streamSummary
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+			t.Fatalf("Failed to decode summary: %v", err)
+		}
+		if !summary.Summary.Success || summary.Summary.Progress.TotalFiles != 5 {
+			t.Errorf("Expected a successful summary of 5 files, got %+v", summary.Summary)
+		}
+	})
+
+	errorTests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{name: "Missing root", body: `{"path": "/does/not/exist"}`, expected: http.StatusNotFound},
+		{
+			name:     "File root",
+			body:     `{"path": "` + filepath.Join(testDir, "small.txt") + `", "config": {"requireDirRoot": true}}`,
+			expected: http.StatusUnprocessableEntity,
+		},
+		{name: "Unknown traversal mode", body: `{"path": "/tmp", "config": {"traversalMode": "sideways"}}`, expected: http.StatusBadRequest},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/scan/stream", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			StreamScan(rec, req)
+
+			if rec.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"filesystem-logger/internal/models"
+	"filesystem-logger/internal/scanner"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true // In production, check origin
+	},
+}
+
+// wsProgressInterval is how often progress is checked during a scan. A
+// frame is only sent when the scan advanced, or as a heartbeat once
+// wsHeartbeatInterval passed without one.
+var (
+	wsProgressInterval  = 500 * time.Millisecond
+	wsHeartbeatInterval = 5 * time.Second
+)
+
+// wsMessage is a command sent by the client
+type wsMessage struct {
+	Action string          `json:"action"`
+	Path   string          `json:"path,omitempty"`
+	Preset string          `json:"preset,omitempty"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// wsFrame is a message sent to the client. Type is one of started,
+// progress, done, cancelled or error.
+type wsFrame struct {
+	Type     string               `json:"type"`
+	Path     string               `json:"path,omitempty"`
+	Progress *models.ScanProgress `json:"progress,omitempty"`
+	Result   *models.ScanResult   `json:"result,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// wsSession holds the state of one WebSocket connection
+type wsSession struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	scanner *scanner.Scanner
+	// aborted is closed by cancel so a scan still waiting for a slot gives
+	// up instead of starting
+	aborted chan struct{}
+}
+
+func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer conn.Close()
+
+	session := &wsSession{conn: conn}
+	// Stop the running scan when the client goes away
+	defer session.cancel()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			session.send(wsFrame{Type: "error", Error: "malformed message: " + err.Error()})
+			continue
+		}
+
+		switch msg.Action {
+		case "start":
+			session.start(msg)
+		case "cancel":
+			if !session.cancel() {
+				session.send(wsFrame{Type: "error", Error: "no scan is running"})
+			}
+		default:
+			session.send(wsFrame{Type: "error", Error: "unknown action: " + msg.Action})
+		}
+	}
+}
+
+func (ws *wsSession) send(frame wsFrame) {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	if err := ws.conn.WriteJSON(frame); err != nil {
+		log.Println(err)
+	}
+}
+
+func (ws *wsSession) start(msg wsMessage) {
+	if msg.Path == "" {
+		ws.send(wsFrame{Type: "error", Error: "path cannot be empty"})
+		return
+	}
+
+	config, err := resolveConfig(msg.Preset, msg.Config)
+	if err != nil {
+		ws.send(wsFrame{Type: "error", Path: msg.Path, Error: err.Error()})
+		return
+	}
+
+	ws.mu.Lock()
+	if ws.scanner != nil {
+		ws.mu.Unlock()
+		ws.send(wsFrame{Type: "error", Path: msg.Path, Error: "a scan is already running"})
+		return
+	}
+	s := scanner.New(config)
+	aborted := make(chan struct{})
+	ws.scanner = s
+	ws.aborted = aborted
+	ws.mu.Unlock()
+
+	ws.send(wsFrame{Type: "started", Path: msg.Path})
+	go ws.run(s, msg.Path, aborted)
+}
+
+// cancel stops the running scan and reports whether there was one
+func (ws *wsSession) cancel() bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.scanner == nil {
+		return false
+	}
+	ws.scanner.Cancel()
+	if ws.aborted != nil {
+		close(ws.aborted)
+		ws.aborted = nil
+	}
+	return true
+}
+
+// finish forgets the scan that ended, so the client can start another one
+// as soon as it sees the final frame
+func (ws *wsSession) finish() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.scanner = nil
+	ws.aborted = nil
+}
+
+// progressFilter suppresses progress frames that would repeat the last one
+type progressFilter struct {
+	heartbeat time.Duration
+	sent      bool
+	scanned   int64
+	blocked   int64
+	lastSent  time.Time
+}
+
+// shouldSend reports whether p is worth sending at now, and records it as
+// sent when it is
+func (f *progressFilter) shouldSend(p *models.ScanProgress, now time.Time) bool {
+	changed := !f.sent || p.ScannedFiles != f.scanned || p.BlockedFiles != f.blocked
+	if !changed && now.Sub(f.lastSent) < f.heartbeat {
+		return false
+	}
+	f.sent = true
+	f.scanned = p.ScannedFiles
+	f.blocked = p.BlockedFiles
+	f.lastSent = now
+	return true
+}
+
+func (ws *wsSession) run(s *scanner.Scanner, path string, aborted <-chan struct{}) {
+	// WebSocket scans count against the same limit as queued ones
+	select {
+	case scanSlots <- struct{}{}:
+		defer func() { <-scanSlots }()
+	case <-aborted:
+		ws.finish()
+		ws.send(wsFrame{Type: "cancelled", Path: path})
+		return
+	}
+
+	// Progress is reported right away and then whenever it changes
+	filter := &progressFilter{heartbeat: wsHeartbeatInterval}
+	progress := s.GetProgress()
+	filter.shouldSend(progress, time.Now())
+	ws.send(wsFrame{Type: "progress", Path: path, Progress: progress})
+
+	ticker := time.NewTicker(wsProgressInterval)
+	stop := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				if progress := s.GetProgress(); filter.shouldSend(progress, now) {
+					ws.send(wsFrame{Type: "progress", Path: path, Progress: progress})
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	result, err := s.Scan(path)
+	close(stop)
+	ws.finish()
+
+	switch {
+	case errors.Is(err, scanner.ErrCancelled):
+		ws.send(wsFrame{Type: "cancelled", Path: path, Result: result})
+	case err != nil:
+		ws.send(wsFrame{Type: "error", Path: path, Error: err.Error()})
+	default:
+		scanMutex.Lock()
+		scanResults[resolveScanPath(path)] = result
+		scanMutex.Unlock()
+		ws.send(wsFrame{Type: "done", Path: path, Result: result})
+	}
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialWebSocket(t *testing.T) *websocket.Conn {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(WebSocketHandler))
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) wsFrame {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var frame wsFrame
+	if err := conn.ReadJSON(&frame); err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	return frame
+}
+
+func TestWebSocketProtocol(t *testing.T) {
+	testDir := setupTestData(t)
+
+	defer func(interval time.Duration) { wsProgressInterval = interval }(wsProgressInterval)
+	wsProgressInterval = 10 * time.Millisecond
+
+	conn := dialWebSocket(t)
+
+	t.Run("Malformed message", func(t *testing.T) {
+		conn.WriteMessage(websocket.TextMessage, []byte("{not json"))
+		if frame := readFrame(t, conn); frame.Type != "error" {
+			t.Errorf("Expected an error frame, got %q", frame.Type)
+		}
+	})
+
+	t.Run("Cancel without scan", func(t *testing.T) {
+		conn.WriteJSON(wsMessage{Action: "cancel"})
+		if frame := readFrame(t, conn); frame.Type != "error" {
+			t.Errorf("Expected an error frame, got %q", frame.Type)
+		}
+	})
+
+	t.Run("Start scan", func(t *testing.T) {
+		// The result is stored under the resolved path, like queued scans
+		link := filepath.Join(t.TempDir(), "link")
+		if err := os.Symlink(testDir, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+
+		conn.WriteJSON(wsMessage{
+			Action: "start",
+			Path:   link,
+			Config: json.RawMessage(`{"maxFileSizeMB": 1, "scanRecursively": true}`),
+		})
+
+		if frame := readFrame(t, conn); frame.Type != "started" {
+			t.Fatalf("Expected a started frame, got %q", frame.Type)
+		}
+
+		progressFrames := 0
+		for {
+			frame := readFrame(t, conn)
+			if frame.Type == "progress" {
+				if frame.Progress == nil {
+					t.Error("Expected progress in progress frame")
+				}
+				progressFrames++
+				continue
+			}
+			if frame.Type != "done" {
+				t.Fatalf("Expected a done frame, got %q: %s", frame.Type, frame.Error)
+			}
+			if frame.Result == nil || frame.Result.Progress.ScannedFiles != 3 {
+				t.Errorf("Expected a result with 3 scanned files, got %+v", frame.Result)
+			}
+			break
+		}
+		if progressFrames == 0 {
+			t.Error("Expected at least one progress frame")
+		}
+
+		id := resolveScanPath(testDir)
+		scanMutex.Lock()
+		_, stored := scanResults[id]
+		_, raw := scanResults[link]
+		delete(scanResults, id)
+		scanMutex.Unlock()
+		if !stored {
+			t.Error("Expected the result to be stored for the status endpoint")
+		}
+		if raw {
+			t.Error("Expected the result not to be stored under the path as given")
+		}
+	})
+
+	t.Run("Waits for a scan slot", func(t *testing.T) {
+		for i := 0; i < cap(scanSlots); i++ {
+			scanSlots <- struct{}{}
+		}
+		defer func() {
+			for i := 0; i < cap(scanSlots); i++ {
+				<-scanSlots
+			}
+		}()
+
+		conn.WriteJSON(wsMessage{Action: "start", Path: testDir})
+		if frame := readFrame(t, conn); frame.Type != "started" {
+			t.Fatalf("Expected a started frame, got %q", frame.Type)
+		}
+		// A scan that got a slot reports progress right away, so the
+		// cancellation must be the next frame
+		conn.WriteJSON(wsMessage{Action: "cancel"})
+		if frame := readFrame(t, conn); frame.Type != "cancelled" {
+			t.Errorf("Expected the waiting scan to be cancelled, got %q", frame.Type)
+		}
+	})
+}
+
+func TestProgressFilter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	filter := &progressFilter{heartbeat: 5 * time.Second}
+
+	tests := []struct {
+		name     string
+		progress models.ScanProgress
+		at       time.Duration
+		expected bool
+	}{
+		{name: "First frame", progress: models.ScanProgress{}, at: 0, expected: true},
+		{name: "Unchanged", progress: models.ScanProgress{}, at: time.Second, expected: false},
+		{name: "Scanned advanced", progress: models.ScanProgress{ScannedFiles: 3}, at: 2 * time.Second, expected: true},
+		{name: "Unchanged again", progress: models.ScanProgress{ScannedFiles: 3}, at: 3 * time.Second, expected: false},
+		{name: "Blocked advanced", progress: models.ScanProgress{ScannedFiles: 3, BlockedFiles: 1}, at: 4 * time.Second, expected: true},
+		{name: "Before heartbeat", progress: models.ScanProgress{ScannedFiles: 3, BlockedFiles: 1}, at: 8 * time.Second, expected: false},
+		{name: "Heartbeat", progress: models.ScanProgress{ScannedFiles: 3, BlockedFiles: 1}, at: 9 * time.Second, expected: true},
+		{name: "After heartbeat", progress: models.ScanProgress{ScannedFiles: 3, BlockedFiles: 1}, at: 10 * time.Second, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.shouldSend(&tt.progress, start.Add(tt.at)); got != tt.expected {
+				t.Errorf("Expected shouldSend %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Age buckets for ScanResult.AgeBuckets. Each bucket holds files younger
+// than its bound and at least as old as the previous one; a month counts as
+// 30 days and a year as 365.
+const (
+	AgeDay   = "<1d"
+	AgeWeek  = "<1w"
+	AgeMonth = "<1m"
+	AgeYear  = "<1y"
+	AgeOlder = "older"
+)
+
+var ageBounds = []struct {
+	limit  time.Duration
+	bucket string
+}{
+	{24 * time.Hour, AgeDay},
+	{7 * 24 * time.Hour, AgeWeek},
+	{30 * 24 * time.Hour, AgeMonth},
+	{365 * 24 * time.Hour, AgeYear},
+}
+
+// AgeBucket returns the bucket for a file last modified at modTime, measured
+// from now. Modification times in the future count as less than a day old.
+func AgeBucket(modTime, now time.Time) string {
+	age := now.Sub(modTime)
+	for _, bound := range ageBounds {
+		if age < bound.limit {
+			return bound.bucket
+		}
+	}
+	return AgeOlder
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgeBucket(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		expected string
+	}{
+		{name: "Future", age: -time.Hour, expected: AgeDay},
+		{name: "Just under a day", age: day - time.Second, expected: AgeDay},
+		{name: "Exactly a day", age: day, expected: AgeWeek},
+		{name: "Exactly a week", age: 7 * day, expected: AgeMonth},
+		{name: "Exactly 30 days", age: 30 * day, expected: AgeYear},
+		{name: "Exactly 365 days", age: 365 * day, expected: AgeOlder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AgeBucket(now.Add(-tt.age), now); got != tt.expected {
+				t.Errorf("Expected bucket %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"mime"
+	"strings"
+)
+
+// Categories returned by CategoryForMime
+const (
+	CategoryImage    = "image"
+	CategoryVideo    = "video"
+	CategoryDocument = "document"
+	CategoryArchive  = "archive"
+	CategoryCode     = "code"
+	CategoryOther    = "other"
+)
+
+// mimeCategories maps sniffed MIME types that identify a category on their
+// own. Image and video types are recognised by their prefix.
+var mimeCategories = map[string]string{
+	"application/pdf":                   CategoryDocument,
+	"application/postscript":            CategoryDocument,
+	"text/rtf":                          CategoryDocument,
+	"application/zip":                   CategoryArchive,
+	"application/x-gzip":                CategoryArchive,
+	"application/x-rar-compressed":      CategoryArchive,
+	"application/x-7z-compressed":       CategoryArchive,
+	"application/vnd.ms-cab-compressed": CategoryArchive,
+}
+
+// extensionCategories maps extensions for content that sniffs as plain text
+// or as a container format, such as Office documents, which are zip files
+var extensionCategories = map[string]string{
+	".doc": CategoryDocument, ".docx": CategoryDocument, ".odt": CategoryDocument,
+	".xls": CategoryDocument, ".xlsx": CategoryDocument, ".ods": CategoryDocument,
+	".ppt": CategoryDocument, ".pptx": CategoryDocument, ".odp": CategoryDocument,
+	".txt": CategoryDocument, ".md": CategoryDocument, ".rtf": CategoryDocument,
+	".csv": CategoryDocument, ".epub": CategoryDocument,
+
+	".tar": CategoryArchive, ".gz": CategoryArchive, ".tgz": CategoryArchive,
+	".bz2": CategoryArchive, ".xz": CategoryArchive, ".zst": CategoryArchive,
+	".zip": CategoryArchive, ".7z": CategoryArchive, ".rar": CategoryArchive,
+
+	".go": CategoryCode, ".py": CategoryCode, ".js": CategoryCode,
+	".ts": CategoryCode, ".java": CategoryCode, ".c": CategoryCode,
+	".h": CategoryCode, ".cpp": CategoryCode, ".cs": CategoryCode,
+	".rb": CategoryCode, ".rs": CategoryCode, ".php": CategoryCode,
+	".sh": CategoryCode, ".html": CategoryCode, ".css": CategoryCode,
+	".json": CategoryCode, ".xml": CategoryCode, ".yaml": CategoryCode,
+	".yml": CategoryCode, ".sql": CategoryCode,
+}
+
+// CategoryForMime buckets a file into image, video, document, archive, code
+// or other. Extensions are consulted first because containers and text
+// files do not reveal what they hold; the sniffed MIME type decides
+// otherwise.
+func CategoryForMime(mimeType, ext string) string {
+	if category, ok := extensionCategories[strings.ToLower(ext)]; ok {
+		return category
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mediaType
+	}
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return CategoryImage
+	case strings.HasPrefix(mimeType, "video/"):
+		return CategoryVideo
+	}
+	if category, ok := mimeCategories[mimeType]; ok {
+		return category
+	}
+	return CategoryOther
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestCategoryForMime(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		ext      string
+		expected string
+	}{
+		{name: "JPEG", mimeType: "image/jpeg", ext: ".jpg", expected: CategoryImage},
+		{name: "Image without extension", mimeType: "image/png", ext: "", expected: CategoryImage},
+		{name: "PDF", mimeType: "application/pdf", ext: ".pdf", expected: CategoryDocument},
+		{name: "Video", mimeType: "video/mp4", ext: ".mp4", expected: CategoryVideo},
+		{name: "Word document", mimeType: "application/zip", ext: ".DOCX", expected: CategoryDocument},
+		{name: "Zip archive", mimeType: "application/zip", ext: ".zip", expected: CategoryArchive},
+		{name: "Source code", mimeType: "text/plain; charset=utf-8", ext: ".go", expected: CategoryCode},
+		{name: "Unknown", mimeType: "application/octet-stream", ext: ".bin", expected: CategoryOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategoryForMime(tt.mimeType, tt.ext); got != tt.expected {
+				t.Errorf("Expected category %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
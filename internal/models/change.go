@@ -0,0 +1,12 @@
+package models
+
+// Change types reported for files when a scan is compared with the state
+// persisted by the previous run
+const (
+	ChangeAdded     = "added"
+	ChangeModified  = "modified"
+	ChangeUnchanged = "unchanged"
+)
+
+// Files of the previous run that are gone are not part of Files; they are
+// listed in ScanResult.DeletedFiles instead.
@@ -0,0 +1,17 @@
+package models
+
+// Decisions recorded in the decision log
+const (
+	DecisionAllow = "allow"
+	DecisionBlock = "block"
+)
+
+// Decision is one line of the decision log: the verdict on a single file
+// and the rule behind it. Rule is empty for files no rule blocked; files
+// unblocked by the allowlist name the allowlist glob.
+type Decision struct {
+	Path     string `json:"path"`
+	Decision string `json:"decision"`
+	Reason   string `json:"reason,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+}
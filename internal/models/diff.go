@@ -0,0 +1,70 @@
+package models
+
+import "sort"
+
+// DiffReport describes the differences between two scan results
+type DiffReport struct {
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// HasChanges reports whether the diff contains any differences
+func (d *DiffReport) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Modified) > 0
+}
+
+// DiffResults compares two scan results keyed by file path. Files are
+// considered modified when their size or modification time differ.
+// Directories are only reported as added or removed.
+func DiffResults(old, new *ScanResult) *DiffReport {
+	report := &DiffReport{
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []string{},
+	}
+
+	oldFiles := indexByPath(old)
+	newFiles := indexByPath(new)
+
+	for path, newFile := range newFiles {
+		oldFile, ok := oldFiles[path]
+		if !ok {
+			report.Added = append(report.Added, path)
+			continue
+		}
+		if newFile.IsDirectory || oldFile.IsDirectory {
+			continue
+		}
+		if isModified(oldFile, newFile) {
+			report.Modified = append(report.Modified, path)
+		}
+	}
+
+	for path := range oldFiles {
+		if _, ok := newFiles[path]; !ok {
+			report.Removed = append(report.Removed, path)
+		}
+	}
+
+	sort.Strings(report.Added)
+	sort.Strings(report.Removed)
+	sort.Strings(report.Modified)
+
+	return report
+}
+
+func indexByPath(result *ScanResult) map[string]FileInfo {
+	index := make(map[string]FileInfo)
+	if result == nil {
+		return index
+	}
+	for _, file := range result.Files {
+		index[file.Path] = file
+	}
+	return index
+}
+
+func isModified(old, new FileInfo) bool {
+	return old.Size != new.Size || !old.ModTime.Equal(new.ModTime)
+}
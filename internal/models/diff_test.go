@@ -0,0 +1,64 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffResults(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	old := &ScanResult{
+		Files: []FileInfo{
+			{Path: "/data", IsDirectory: true},
+			{Path: "/data/unchanged.txt", Size: 100, ModTime: modTime},
+			{Path: "/data/changed.txt", Size: 100, ModTime: modTime},
+			{Path: "/data/removed.txt", Size: 50, ModTime: modTime},
+		},
+	}
+	updated := &ScanResult{
+		Files: []FileInfo{
+			{Path: "/data", IsDirectory: true},
+			{Path: "/data/unchanged.txt", Size: 100, ModTime: modTime},
+			{Path: "/data/changed.txt", Size: 120, ModTime: modTime.Add(time.Minute)},
+			{Path: "/data/added.txt", Size: 10, ModTime: modTime},
+		},
+	}
+
+	report := DiffResults(old, updated)
+
+	tests := []struct {
+		name     string
+		got      []string
+		expected []string
+	}{
+		{name: "Added file", got: report.Added, expected: []string{"/data/added.txt"}},
+		{name: "Removed file", got: report.Removed, expected: []string{"/data/removed.txt"}},
+		{name: "Modified file", got: report.Modified, expected: []string{"/data/changed.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.got)
+			}
+		})
+	}
+
+	t.Run("Unchanged file", func(t *testing.T) {
+		for _, list := range [][]string{report.Added, report.Removed, report.Modified} {
+			for _, path := range list {
+				if path == "/data/unchanged.txt" || path == "/data" {
+					t.Errorf("Expected %s to be unchanged", path)
+				}
+			}
+		}
+	})
+
+	t.Run("Identical results", func(t *testing.T) {
+		if DiffResults(old, old).HasChanges() {
+			t.Error("Expected no changes when diffing a result with itself")
+		}
+	})
+}
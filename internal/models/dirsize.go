@@ -0,0 +1,53 @@
+package models
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AggregateDirSizes sets the Size of every directory entry in files to the
+// total size of the files below it. File sizes are first added to their
+// parent directory, then directories are folded into their parents from the
+// deepest up. Directories listed but not walked keep a size of zero.
+func AggregateDirSizes(files []FileInfo) {
+	dirs := make(map[string]int)
+	for i, file := range files {
+		if file.IsDirectory {
+			dirs[file.Path] = i
+			files[i].Size = 0
+		}
+	}
+
+	for _, file := range files {
+		if file.IsDirectory {
+			continue
+		}
+		if i, ok := dirs[filepath.Dir(file.Path)]; ok {
+			files[i].Size += file.Size
+		}
+	}
+
+	order := make([]int, 0, len(dirs))
+	for _, i := range dirs {
+		order = append(order, i)
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return pathDepth(files[order[a]].Path) > pathDepth(files[order[b]].Path)
+	})
+	for _, i := range order {
+		path := files[i].Path
+		parent := filepath.Dir(path)
+		if parent == path {
+			continue
+		}
+		if p, ok := dirs[parent]; ok {
+			files[p].Size += files[i].Size
+		}
+	}
+}
+
+// pathDepth counts the separators in a cleaned path
+func pathDepth(path string) int {
+	return strings.Count(filepath.Clean(path), string(filepath.Separator))
+}
@@ -0,0 +1,108 @@
+package models
+
+import "strings"
+
+// Filter returns a new result containing only the files matching pred. The
+// summary counters and per-category, per-reason and per-age breakdowns are
+// recomputed from the retained files. Counters that cannot be derived from
+// them, such as BytesRead, SampledOut and PermissionDenied, are zero.
+func (r *ScanResult) Filter(pred func(FileInfo) bool) *ScanResult {
+	filtered := &ScanResult{
+		Files:    []FileInfo{},
+		Duration: r.Duration,
+		Success:  r.Success,
+		Error:    r.Error,
+		Progress: ScanProgress{
+			Errors:           append([]string(nil), r.Progress.Errors...),
+			StartTime:        r.Progress.StartTime,
+			LastUpdated:      r.Progress.LastUpdated,
+			CurrentDirectory: r.Progress.CurrentDirectory,
+			Paused:           r.Progress.Paused,
+		},
+
+		DurationHuman: r.DurationHuman,
+		HashAlgorithm: r.HashAlgorithm,
+		Root:          r.Root,
+	}
+
+	progress := &filtered.Progress
+	started := !r.Progress.StartTime.IsZero()
+	for _, file := range r.Files {
+		if !pred(file) {
+			continue
+		}
+		filtered.Files = append(filtered.Files, file)
+		progress.TotalFiles++
+		progress.TotalSize += file.Size
+		if !file.IsDirectory {
+			progress.ScannedFiles++
+			if !file.IsDuplicateLink {
+				progress.ScannedSize += file.Size
+			}
+			if started && file.ModTime.After(r.Progress.StartTime) {
+				progress.ModifiedDuringScan++
+			}
+		}
+		if file.IsBlocked {
+			progress.BlockedFiles++
+			progress.BlockedSize += file.Size
+			if filtered.BlockedByReason == nil {
+				filtered.BlockedByReason = make(map[string][]string)
+			}
+			group := ReasonGroup(file.BlockReason)
+			filtered.BlockedByReason[group] = append(filtered.BlockedByReason[group], file.Path)
+		}
+		if file.Vanished {
+			progress.VanishedFiles++
+		}
+		if file.IsEmpty {
+			progress.EmptyFiles++
+		}
+		if file.IsSymlink {
+			progress.SymlinkCount++
+		}
+		if file.BrokenSymlink {
+			progress.BrokenSymlinks++
+		}
+		switch file.EntryType {
+		case EntryDevice:
+			progress.DeviceCount++
+		case EntrySocket:
+			progress.SocketCount++
+		case EntryPipe:
+			progress.PipeCount++
+		case EntryFile:
+			if !started {
+				break
+			}
+			if filtered.AgeBuckets == nil {
+				filtered.AgeBuckets = make(map[string]int64)
+			}
+			filtered.AgeBuckets[AgeBucket(file.ModTime, r.Progress.StartTime)]++
+		}
+		if file.Category != "" {
+			if filtered.CategoryStats == nil {
+				filtered.CategoryStats = make(map[string]int64)
+			}
+			filtered.CategoryStats[file.Category]++
+		}
+	}
+
+	return filtered
+}
+
+// OnlyBlocked matches blocked files
+func OnlyBlocked() func(FileInfo) bool {
+	return func(file FileInfo) bool {
+		return file.IsBlocked
+	}
+}
+
+// ByExtension matches files with the given extension, with or without the
+// leading dot and ignoring case
+func ByExtension(ext string) func(FileInfo) bool {
+	ext = "." + strings.TrimPrefix(ext, ".")
+	return func(file FileInfo) bool {
+		return !file.IsDirectory && strings.EqualFold(file.Extension, ext)
+	}
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	result := &ScanResult{
+		Files: []FileInfo{
+			{Path: "/data", IsDirectory: true},
+			{Path: "/data/a.txt", Extension: ".txt", Size: 100},
+			{Path: "/data/b.txt", Extension: ".txt", Size: 200, IsBlocked: true},
+			{Path: "/data/c.log", Extension: ".log", Size: 400, IsBlocked: true},
+		},
+		Progress: ScanProgress{
+			TotalFiles:   4,
+			ScannedFiles: 3,
+			BlockedFiles: 2,
+			TotalSize:    700,
+			ScannedSize:  700,
+		},
+		Success: true,
+	}
+
+	tests := []struct {
+		name          string
+		pred          func(FileInfo) bool
+		expectedFiles int
+		expectedBlock int64
+		expectedSize  int64
+	}{
+		{name: "Only blocked", pred: OnlyBlocked(), expectedFiles: 2, expectedBlock: 2, expectedSize: 600},
+		{name: "By extension with dot", pred: ByExtension(".txt"), expectedFiles: 2, expectedBlock: 1, expectedSize: 300},
+		{name: "By extension without dot", pred: ByExtension("LOG"), expectedFiles: 1, expectedBlock: 1, expectedSize: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := result.Filter(tt.pred)
+
+			if len(filtered.Files) != tt.expectedFiles {
+				t.Errorf("Expected %d files, got %d", tt.expectedFiles, len(filtered.Files))
+			}
+			if filtered.Progress.ScannedFiles != int64(len(filtered.Files)) {
+				t.Errorf("Expected ScannedFiles=%d, got %d", len(filtered.Files), filtered.Progress.ScannedFiles)
+			}
+			if filtered.Progress.BlockedFiles != tt.expectedBlock {
+				t.Errorf("Expected BlockedFiles=%d, got %d", tt.expectedBlock, filtered.Progress.BlockedFiles)
+			}
+			if filtered.Progress.TotalSize != tt.expectedSize {
+				t.Errorf("Expected TotalSize=%d, got %d", tt.expectedSize, filtered.Progress.TotalSize)
+			}
+		})
+	}
+
+	if len(result.Files) != 4 || result.Progress.BlockedFiles != 2 {
+		t.Error("Expected the original result to be left untouched")
+	}
+}
+
+func TestFilterRecomputesSummary(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	result := &ScanResult{
+		Files: []FileInfo{
+			{Path: "docs", IsDirectory: true, EntryType: EntryDir},
+			{Path: "docs/a.txt", Size: 10, EntryType: EntryFile, Category: "document", ModTime: start.Add(-time.Hour)},
+			{Path: "docs/empty.txt", EntryType: EntryFile, Category: "document", IsEmpty: true, ModTime: start.Add(time.Minute),
+				IsBlocked: true, BlockReason: "Empty file"},
+			{Path: "docs/link", EntryType: EntrySymlink, IsSymlink: true, BrokenSymlink: true},
+			{Path: "docs/gone.txt", EntryType: EntryFile, Vanished: true},
+			{Path: "big.iso", Size: 500, EntryType: EntryFile, Category: "archive", ModTime: start.Add(-400 * 24 * time.Hour),
+				IsBlocked: true, BlockReason: "File size exceeds limit: 500 bytes > 0 MB"},
+			{Path: "fifo", EntryType: EntryPipe},
+		},
+		Progress: ScanProgress{
+			TotalFiles:       7,
+			EmptyFiles:       1,
+			SymlinkCount:     1,
+			BrokenSymlinks:   1,
+			VanishedFiles:    1,
+			PipeCount:        1,
+			PermissionDenied: 3,
+			SampledOut:       4,
+			BytesRead:        510,
+			StartTime:        start,
+			Errors:           []string{"error reading directory x"},
+		},
+		Root:          "/data",
+		HashAlgorithm: HashSHA256,
+	}
+
+	filtered := result.Filter(func(file FileInfo) bool { return file.Path != "big.iso" && file.Path != "fifo" })
+
+	expected := ScanProgress{
+		TotalFiles:         5,
+		ScannedFiles:       4,
+		TotalSize:          10,
+		ScannedSize:        10,
+		BlockedFiles:       1,
+		VanishedFiles:      1,
+		EmptyFiles:         1,
+		SymlinkCount:       1,
+		BrokenSymlinks:     1,
+		ModifiedDuringScan: 1,
+		StartTime:          start,
+		Errors:             []string{"error reading directory x"},
+	}
+	if !reflect.DeepEqual(filtered.Progress, expected) {
+		t.Errorf("Expected progress %+v, got %+v", expected, filtered.Progress)
+	}
+	if filtered.Root != "/data" || filtered.HashAlgorithm != HashSHA256 {
+		t.Errorf("Expected Root and HashAlgorithm to be kept, got %q and %q", filtered.Root, filtered.HashAlgorithm)
+	}
+	if expected := map[string]int64{"document": 2}; !reflect.DeepEqual(filtered.CategoryStats, expected) {
+		t.Errorf("Expected category stats %v, got %v", expected, filtered.CategoryStats)
+	}
+	if expected := map[string][]string{"Empty file": {"docs/empty.txt"}}; !reflect.DeepEqual(filtered.BlockedByReason, expected) {
+		t.Errorf("Expected blocked by reason %v, got %v", expected, filtered.BlockedByReason)
+	}
+	if expected := map[string]int64{AgeDay: 2, AgeOlder: 1}; !reflect.DeepEqual(filtered.AgeBuckets, expected) {
+		t.Errorf("Expected age buckets %v, got %v", expected, filtered.AgeBuckets)
+	}
+}
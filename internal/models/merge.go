@@ -0,0 +1,164 @@
+package models
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// MergeResults combines the results of scans over different roots, for
+// example from several machines, into one. Files are concatenated in order;
+// a file reported by more than one result is kept once, from the first, and
+// its entry is taken out of the counters of the later results. Counters,
+// sizes and statistics are summed, errors and deleted files are united,
+// and the longest duration and deepest and widest directories are kept. Nil
+// results are skipped.
+func MergeResults(results ...*ScanResult) *ScanResult {
+	merged := &ScanResult{Success: true}
+	seen := make(map[string]bool)
+	errors := make(map[string]bool)
+	blocked := make(map[string]map[string]bool)
+	deleted := make(map[string]bool)
+	first := true
+
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+
+		p := &merged.Progress
+		p.TotalFiles += r.Progress.TotalFiles
+		p.ScannedFiles += r.Progress.ScannedFiles
+		p.TotalSize += r.Progress.TotalSize
+		p.ScannedSize += r.Progress.ScannedSize
+		p.BlockedFiles += r.Progress.BlockedFiles
+		p.BlockedSize += r.Progress.BlockedSize
+		p.VanishedFiles += r.Progress.VanishedFiles
+		p.EmptyFiles += r.Progress.EmptyFiles
+		p.SymlinkCount += r.Progress.SymlinkCount
+		p.BrokenSymlinks += r.Progress.BrokenSymlinks
+		p.PermissionDenied += r.Progress.PermissionDenied
+		p.DeviceCount += r.Progress.DeviceCount
+		p.SocketCount += r.Progress.SocketCount
+		p.PipeCount += r.Progress.PipeCount
+		p.BytesRead += r.Progress.BytesRead
+		p.ModifiedDuringScan += r.Progress.ModifiedDuringScan
+		p.SampledOut += r.Progress.SampledOut
+		p.ReadBudgetExhausted = p.ReadBudgetExhausted || r.Progress.ReadBudgetExhausted
+		if first || r.Progress.StartTime.Before(p.StartTime) {
+			p.StartTime = r.Progress.StartTime
+		}
+		if r.Progress.LastUpdated.After(p.LastUpdated) {
+			p.LastUpdated = r.Progress.LastUpdated
+		}
+		for _, err := range r.Progress.Errors {
+			if !errors[err] {
+				errors[err] = true
+				p.Errors = append(p.Errors, err)
+			}
+		}
+
+		if r.Duration > merged.Duration {
+			merged.Duration = r.Duration
+			merged.DurationHuman = r.Duration.String()
+		}
+		merged.Success = merged.Success && r.Success
+		if merged.Error == "" {
+			merged.Error = r.Error
+		}
+		if first {
+			merged.Root = r.Root
+		} else if merged.Root != r.Root {
+			merged.Root = ""
+		}
+		if r.MaxDepthSeen > merged.MaxDepthSeen {
+			merged.MaxDepthSeen, merged.DeepestPath = r.MaxDepthSeen, r.DeepestPath
+		}
+		if r.WidestDirCount > merged.WidestDirCount {
+			merged.WidestDirCount, merged.WidestDir = r.WidestDirCount, r.WidestDir
+		}
+		merged.CategoryStats = addCounts(merged.CategoryStats, r.CategoryStats)
+		merged.AgeBuckets = addCounts(merged.AgeBuckets, r.AgeBuckets)
+		for _, path := range r.DeletedFiles {
+			if !deleted[path] {
+				deleted[path] = true
+				merged.DeletedFiles = append(merged.DeletedFiles, path)
+			}
+		}
+		for reason, paths := range r.BlockedByReason {
+			if blocked[reason] == nil {
+				blocked[reason] = make(map[string]bool)
+			}
+			for _, path := range paths {
+				blocked[reason][path] = true
+			}
+		}
+
+		for _, file := range r.Files {
+			key := mergeKey(r.Root, file.Path)
+			if !seen[key] {
+				seen[key] = true
+				merged.Files = append(merged.Files, file)
+				continue
+			}
+			uncount(merged, file, r)
+		}
+		first = false
+	}
+
+	if len(blocked) > 0 {
+		merged.BlockedByReason = make(map[string][]string, len(blocked))
+		for reason, paths := range blocked {
+			for path := range paths {
+				merged.BlockedByReason[reason] = append(merged.BlockedByReason[reason], path)
+			}
+			sort.Strings(merged.BlockedByReason[reason])
+		}
+	}
+
+	return merged
+}
+
+// mergeKey identifies a file across results. Relative paths are only the
+// same file when they are relative to the same root.
+func mergeKey(root, path string) string {
+	if root != "" && !filepath.IsAbs(path) {
+		return filepath.Join(root, path)
+	}
+	return path
+}
+
+// uncount takes a duplicate file out of the merged counters it was added to
+// as part of r. Statistics r did not collect are left alone.
+func uncount(merged *ScanResult, file FileInfo, r *ScanResult) {
+	p := &merged.Progress
+	p.TotalFiles--
+	if file.IsDirectory {
+		return
+	}
+	p.ScannedFiles--
+	p.TotalSize -= file.Size
+	p.ScannedSize -= file.Size
+	if file.IsBlocked {
+		p.BlockedFiles--
+		p.BlockedSize -= file.Size
+	}
+	if file.Category != "" && r.CategoryStats[file.Category] > 0 {
+		merged.CategoryStats[file.Category]--
+	}
+	if bucket := AgeBucket(file.ModTime, r.Progress.StartTime); file.EntryType == EntryFile && r.AgeBuckets[bucket] > 0 {
+		merged.AgeBuckets[bucket]--
+	}
+}
+
+func addCounts(into, from map[string]int64) map[string]int64 {
+	if len(from) == 0 {
+		return into
+	}
+	if into == nil {
+		into = make(map[string]int64, len(from))
+	}
+	for key, count := range from {
+		into[key] += count
+	}
+	return into
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeResults(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	first := &ScanResult{
+		Files: []FileInfo{
+			{Path: "/data", IsDirectory: true},
+			{Path: "/data/a.txt", Size: 100},
+			{Path: "/data/shared.txt", Size: 40, IsBlocked: true},
+		},
+		Progress: ScanProgress{
+			TotalFiles: 3, ScannedFiles: 2, TotalSize: 140, ScannedSize: 140,
+			BlockedFiles: 1, Errors: []string{"permission denied: /data/x"}, StartTime: start,
+		},
+		Duration: 2 * time.Second,
+		Success:  true,
+	}
+	// Tweede scan overlapt met de eerste op /data/shared.txt
+	second := &ScanResult{
+		Files: []FileInfo{
+			{Path: "/other/b.txt", Size: 10},
+			{Path: "/data/shared.txt", Size: 40, IsBlocked: true},
+		},
+		Progress: ScanProgress{
+			TotalFiles: 2, ScannedFiles: 2, TotalSize: 50, ScannedSize: 50,
+			BlockedFiles: 1, Errors: []string{"permission denied: /data/x", "timeout"}, StartTime: start.Add(time.Minute),
+		},
+		Duration: 5 * time.Second,
+		Success:  true,
+	}
+
+	merged := MergeResults(first, nil, second)
+
+	var paths []string
+	for _, file := range merged.Files {
+		paths = append(paths, file.Path)
+	}
+	expectedPaths := []string{"/data", "/data/a.txt", "/data/shared.txt", "/other/b.txt"}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("Expected files %v, got %v", expectedPaths, paths)
+	}
+
+	tests := []struct {
+		name     string
+		got      int64
+		expected int64
+	}{
+		{name: "Total files", got: merged.Progress.TotalFiles, expected: 4},
+		{name: "Scanned files", got: merged.Progress.ScannedFiles, expected: 3},
+		{name: "Total size", got: merged.Progress.TotalSize, expected: 150},
+		{name: "Scanned size", got: merged.Progress.ScannedSize, expected: 150},
+		{name: "Blocked files", got: merged.Progress.BlockedFiles, expected: 1},
+		{name: "Errors", got: int64(len(merged.Progress.Errors)), expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, tt.got)
+			}
+		})
+	}
+
+	if merged.Duration != 5*time.Second {
+		t.Errorf("Expected the longest duration 5s, got %v", merged.Duration)
+	}
+	if !merged.Progress.StartTime.Equal(start) {
+		t.Errorf("Expected the earliest start time %v, got %v", start, merged.Progress.StartTime)
+	}
+	if !merged.Success {
+		t.Error("Expected the merged result to succeed")
+	}
+}
+
+func TestMergeResultsWithoutStats(t *testing.T) {
+	// Geen van beide resultaten heeft CategoryStats of AgeBuckets
+	shared := FileInfo{Path: "/data/photo.jpg", Size: 10, Category: "image", EntryType: EntryFile}
+	first := &ScanResult{Files: []FileInfo{shared}, Progress: ScanProgress{TotalFiles: 1, ScannedFiles: 1}}
+	second := &ScanResult{Files: []FileInfo{shared}, Progress: ScanProgress{TotalFiles: 1, ScannedFiles: 1}}
+
+	merged := MergeResults(first, second)
+
+	if len(merged.Files) != 1 || merged.Progress.TotalFiles != 1 {
+		t.Errorf("Expected the shared file once, got %d files and TotalFiles %d", len(merged.Files), merged.Progress.TotalFiles)
+	}
+	if merged.CategoryStats != nil || merged.AgeBuckets != nil {
+		t.Errorf("Expected no statistics, got %v and %v", merged.CategoryStats, merged.AgeBuckets)
+	}
+}
+
+func TestMergeResultsRelativePaths(t *testing.T) {
+	first := &ScanResult{
+		Root:     "/mnt/a",
+		Files:    []FileInfo{{Path: "a/b.exe", Size: 10, IsBlocked: true}},
+		Progress: ScanProgress{TotalFiles: 1, ScannedFiles: 1, BlockedFiles: 1},
+	}
+	second := &ScanResult{
+		Root:     "/mnt/b",
+		Files:    []FileInfo{{Path: "a/b.exe", Size: 20, IsBlocked: true}},
+		Progress: ScanProgress{TotalFiles: 1, ScannedFiles: 1, BlockedFiles: 1},
+	}
+	again := &ScanResult{
+		Root:     "/mnt/a",
+		Files:    []FileInfo{{Path: "a/b.exe", Size: 10, IsBlocked: true}},
+		Progress: ScanProgress{TotalFiles: 1, ScannedFiles: 1, BlockedFiles: 1},
+	}
+
+	merged := MergeResults(first, second, again)
+
+	if len(merged.Files) != 2 {
+		t.Errorf("Expected a/b.exe from both roots, got %v", merged.Files)
+	}
+	if merged.Progress.BlockedFiles != 2 || merged.Progress.ScannedFiles != 2 {
+		t.Errorf("Expected 2 blocked and 2 scanned files, got %d and %d", merged.Progress.BlockedFiles, merged.Progress.ScannedFiles)
+	}
+}
+
+func TestMergeResultsDeletedFiles(t *testing.T) {
+	merged := MergeResults(
+		&ScanResult{DeletedFiles: []string{"/a/gone.txt", "/shared/old.txt"}},
+		&ScanResult{DeletedFiles: []string{"/shared/old.txt", "/b/gone.txt"}},
+	)
+
+	expected := []string{"/a/gone.txt", "/shared/old.txt", "/b/gone.txt"}
+	if !reflect.DeepEqual(merged.DeletedFiles, expected) {
+		t.Errorf("Expected deleted files %v, got %v", expected, merged.DeletedFiles)
+	}
+}
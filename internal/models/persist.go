@@ -0,0 +1,49 @@
+package models
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SaveResult writes the full scan result to path as gzip-compressed JSON
+func SaveResult(r *ScanResult, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create result file: %v", err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	if err := json.NewEncoder(gz).Encode(r); err != nil {
+		return fmt.Errorf("failed to encode result: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress result: %v", err)
+	}
+
+	return file.Close()
+}
+
+// LoadResult reads a scan result written by SaveResult
+func LoadResult(path string) (*ScanResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open result file: %v", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress result: %v", err)
+	}
+	defer gz.Close()
+
+	var result ScanResult
+	if err := json.NewDecoder(gz).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %v", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadResult(t *testing.T) {
+	modTime := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	original := &ScanResult{
+		Files: []FileInfo{
+			{Path: "/data", Name: "data", IsDirectory: true},
+			{
+				Path:        "/data/report.pdf",
+				Name:        "report.pdf",
+				Size:        2048,
+				FileType:    "pdf",
+				MimeType:    "application/pdf",
+				Extension:   ".pdf",
+				ModTime:     modTime,
+				IsBlocked:   true,
+				BlockReason: "File size exceeds limit",
+				Entropy:     7.5,
+			},
+		},
+		Progress: ScanProgress{
+			TotalFiles:   2,
+			ScannedFiles: 1,
+			TotalSize:    2048,
+			ScannedSize:  2048,
+			BlockedFiles: 1,
+			Errors:       []string{"error reading directory /data/locked"},
+			StartTime:    modTime,
+			LastUpdated:  modTime.Add(time.Second),
+		},
+		Duration:   1500 * time.Millisecond,
+		Success:    false,
+		HardLinks:  []HardLinkGroup{{Device: 2049, Inode: 42, Paths: []string{"/data/a", "/data/b"}}},
+		UniqueSize: 1024,
+	}
+
+	path := filepath.Join(t.TempDir(), "result.json.gz")
+	if err := SaveResult(original, path); err != nil {
+		t.Fatalf("SaveResult failed: %v", err)
+	}
+
+	loaded, err := LoadResult(path)
+	if err != nil {
+		t.Fatalf("LoadResult failed: %v", err)
+	}
+
+	if loaded.Duration != original.Duration {
+		t.Errorf("Expected Duration %v, got %v", original.Duration, loaded.Duration)
+	}
+	if !reflect.DeepEqual(loaded.Files, original.Files) {
+		t.Errorf("Files differ:\nexpected %+v\ngot      %+v", original.Files, loaded.Files)
+	}
+	if !reflect.DeepEqual(loaded.Progress, original.Progress) {
+		t.Errorf("Progress differs:\nexpected %+v\ngot      %+v", original.Progress, loaded.Progress)
+	}
+	if loaded.Success != original.Success || loaded.UniqueSize != original.UniqueSize {
+		t.Errorf("Summary differs: expected %v/%d, got %v/%d",
+			original.Success, original.UniqueSize, loaded.Success, loaded.UniqueSize)
+	}
+	if !reflect.DeepEqual(loaded.HardLinks, original.HardLinks) {
+		t.Errorf("Expected HardLinks %v, got %v", original.HardLinks, loaded.HardLinks)
+	}
+}
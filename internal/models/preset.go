@@ -0,0 +1,74 @@
+package models
+
+import "sort"
+
+// presets builds the predefined configurations by name. They are functions
+// so every caller gets its own copy of the slices.
+var presets = map[string]func() ScanConfig{
+	"images": func() ScanConfig {
+		return ScanConfig{
+			MaxFileSizeMB:   100,
+			ScanRecursively: true,
+			AllowedTypes: []string{
+				".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".svg", ".tiff", ".heic",
+			},
+		}
+	},
+	"documents": func() ScanConfig {
+		return ScanConfig{
+			MaxFileSizeMB:   50,
+			ScanRecursively: true,
+			AllowedTypes: []string{
+				".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
+				".odt", ".ods", ".odp", ".rtf", ".txt", ".md", ".csv",
+			},
+		}
+	},
+	"source": func() ScanConfig {
+		return ScanConfig{
+			MaxFileSizeMB:   5,
+			ScanRecursively: true,
+			AllowedTypes: []string{
+				".go", ".py", ".js", ".ts", ".java", ".c", ".h", ".cpp", ".cs", ".rs",
+				".rb", ".php", ".html", ".css", ".json", ".yaml", ".yml", ".toml", ".md",
+			},
+			BlockedPatterns: []string{"*.min.js", "*.map"},
+		}
+	},
+	"security": func() ScanConfig {
+		return ScanConfig{
+			MaxFileSizeMB:   500,
+			ScanRecursively: true,
+			BlockedPatterns: []string{
+				"*.exe", "*.dll", "*.scr", "*.bat", "*.cmd", "*.ps1", "*.vbs", "*.jar",
+				"*.pem", "*.key", "id_rsa*", ".env",
+			},
+			FlagExtensionMismatch:  true,
+			BlockExtensionMismatch: true,
+			ComputeEntropy:         true,
+			ComputeHash:            true,
+			FlagWorldWritable:      true,
+			BlockWorldWritable:     true,
+		}
+	},
+}
+
+// Preset returns the predefined configuration with the given name and
+// whether it exists. Callers may override any field of the returned config.
+func Preset(name string) (ScanConfig, bool) {
+	build, ok := presets[name]
+	if !ok {
+		return ScanConfig{}, false
+	}
+	return build(), true
+}
+
+// PresetNames returns the names of all presets in alphabetical order
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPreset(t *testing.T) {
+	tests := []struct {
+		name         string
+		ok           bool
+		allowedTypes []string
+	}{
+		{name: "images", ok: true, allowedTypes: []string{".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".svg", ".tiff", ".heic"}},
+		{name: "documents", ok: true, allowedTypes: []string{".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".odt", ".ods", ".odp", ".rtf", ".txt", ".md", ".csv"}},
+		{name: "source", ok: true, allowedTypes: []string{".go", ".py", ".js", ".ts", ".java", ".c", ".h", ".cpp", ".cs", ".rs", ".rb", ".php", ".html", ".css", ".json", ".yaml", ".yml", ".toml", ".md"}},
+		{name: "security", ok: true, allowedTypes: nil},
+		{name: "unknown", ok: false, allowedTypes: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, ok := Preset(tt.name)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok=%v, got %v", tt.ok, ok)
+			}
+			if !reflect.DeepEqual(config.AllowedTypes, tt.allowedTypes) {
+				t.Errorf("Expected allowed types %v, got %v", tt.allowedTypes, config.AllowedTypes)
+			}
+		})
+	}
+
+	t.Run("Security blocks executables", func(t *testing.T) {
+		config, _ := Preset("security")
+		if len(config.BlockedPatterns) == 0 || !config.BlockExtensionMismatch {
+			t.Errorf("Expected security preset to block executables and spoofed files, got %+v", config)
+		}
+	})
+
+	t.Run("Returns a copy", func(t *testing.T) {
+		config, _ := Preset("images")
+		config.AllowedTypes[0] = ".exe"
+		if again, _ := Preset("images"); again.AllowedTypes[0] != ".jpg" {
+			t.Error("Expected modifying a preset not to affect later calls")
+		}
+	})
+
+	t.Run("Names", func(t *testing.T) {
+		expected := []string{"documents", "images", "security", "source"}
+		if names := PresetNames(); !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package models
+
+import "strings"
+
+// ReasonGroup strips the per-file details from a block reason, giving the
+// keys of ScanResult.BlockedByReason. Built-in reasons put them after the
+// first ": ", so "File size exceeds limit: 12 bytes > 0 MB" groups as "File
+// size exceeds limit". Reasons without details, such as most custom rule
+// reasons, are kept whole.
+func ReasonGroup(reason string) string {
+	group, _, _ := strings.Cut(reason, ": ")
+	return group
+}
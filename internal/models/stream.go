@@ -0,0 +1,98 @@
+package models
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// StreamResultFiles calls fn for every file of the result stored at path,
+// decoding one entry at a time so memory use stays bounded however large the
+// result is. It reads results written by SaveResult as well as plain JSON,
+// taking the files from the top-level "files" array or, for blocked file
+// exports, the "blockedFiles" array. An error returned by fn stops the
+// stream and is returned as is.
+func StreamResultFiles(path string, fn func(FileInfo) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open result file: %v", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = bufio.NewReader(file)
+	if magic, _ := r.(*bufio.Reader).Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("failed to decompress result: %v", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode result: %v", err)
+		}
+
+		if key := token.(string); key != "files" && key != "blockedFiles" {
+			// Other values are small compared to the file list
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("failed to decode result: %v", err)
+			}
+			continue
+		}
+
+		if err := streamFileArray(dec, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamFileArray decodes a JSON array of files, or null, element by element
+func streamFileArray(dec *json.Decoder, fn func(FileInfo) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode result: %v", err)
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("failed to decode result: expected file array, got %v", token)
+	}
+
+	for dec.More() {
+		var file FileInfo
+		if err := dec.Decode(&file); err != nil {
+			return fmt.Errorf("failed to decode file: %v", err)
+		}
+		if err := fn(file); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode result: %v", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("failed to decode result: expected %v, got %v", want, token)
+	}
+	return nil
+}
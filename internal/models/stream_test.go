@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStreamResultFiles(t *testing.T) {
+	const fileCount = 20000
+
+	result := &ScanResult{Progress: ScanProgress{TotalFiles: fileCount}, Success: true}
+	for i := 0; i < fileCount; i++ {
+		result.Files = append(result.Files, FileInfo{
+			Path:      fmt.Sprintf("/data/dir%03d/file%05d.bin", i%100, i),
+			Name:      fmt.Sprintf("file%05d.bin", i),
+			Size:      int64(i),
+			IsBlocked: i%2 == 0,
+		})
+	}
+
+	tempDir := t.TempDir()
+	savedPath := filepath.Join(tempDir, "result.json.gz")
+	if err := SaveResult(result, savedPath); err != nil {
+		t.Fatalf("SaveResult failed: %v", err)
+	}
+
+	// Zelfde vorm als de export van geblokkeerde bestanden
+	exportPath := filepath.Join(tempDir, "blocked_files.json")
+	exported, _ := json.Marshal(map[string]interface{}{
+		"timestamp":    "2024-01-01T00:00:00Z",
+		"blockedFiles": result.Files[:100],
+		"blockedCount": 100,
+	})
+	if err := os.WriteFile(exportPath, exported, 0644); err != nil {
+		t.Fatalf("Failed to write export: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected int
+	}{
+		{name: "Saved result", path: savedPath, expected: fileCount},
+		{name: "Blocked file export", path: exportPath, expected: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visited := 0
+			var size int64
+			err := StreamResultFiles(tt.path, func(file FileInfo) error {
+				if file.Path != result.Files[visited].Path {
+					t.Fatalf("Expected %s at position %d, got %s", result.Files[visited].Path, visited, file.Path)
+				}
+				visited++
+				size += file.Size
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("StreamResultFiles failed: %v", err)
+			}
+			if visited != tt.expected {
+				t.Errorf("Expected %d files, visited %d", tt.expected, visited)
+			}
+			if expected := int64(tt.expected * (tt.expected - 1) / 2); size != expected {
+				t.Errorf("Expected total size %d, got %d", expected, size)
+			}
+		})
+	}
+
+	t.Run("Callback error stops the stream", func(t *testing.T) {
+		stop := errors.New("stop")
+		visited := 0
+		err := StreamResultFiles(savedPath, func(file FileInfo) error {
+			visited++
+			if visited == 10 {
+				return stop
+			}
+			return nil
+		})
+		if err != stop || visited != 10 {
+			t.Errorf("Expected to stop after 10 files with the callback error, got %d files and %v", visited, err)
+		}
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		invalid := filepath.Join(tempDir, "invalid.json")
+		os.WriteFile(invalid, []byte(`["not", "an", "object"]`), 0644)
+		if err := StreamResultFiles(invalid, func(FileInfo) error { return nil }); err == nil {
+			t.Error("Expected an error for a JSON array")
+		}
+	})
+}
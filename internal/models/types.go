@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"io/fs"
+	"time"
+)
 
 // FileInfo represents metadata about a file
 type FileInfo struct {
@@ -9,23 +12,313 @@ type FileInfo struct {
 	Size        int64     `json:"size"`
 	FileType    string    `json:"fileType"`
 	MimeType    string    `json:"mimeType"`
+	Category    string    `json:"category,omitempty"`
 	Extension   string    `json:"extension"`
 	ModTime     time.Time `json:"modTime"`
+	CreatedTime time.Time `json:"createdTime"`
 	IsDirectory bool      `json:"isDirectory"`
 	IsBlocked   bool      `json:"isBlocked"`
+	IsSymlink   bool      `json:"isSymlink,omitempty"`
+	Vanished    bool      `json:"vanished,omitempty"`
+	IsEmpty     bool      `json:"isEmpty,omitempty"`
+	EntryType   string    `json:"entryType,omitempty"`
 	BlockReason string    `json:"blockReason,omitempty"`
 	AccessError string    `json:"accessError,omitempty"`
+
+	ExtensionMismatch bool    `json:"extensionMismatch,omitempty"`
+	Entropy           float64 `json:"entropy,omitempty"`
+	Device            uint64  `json:"device,omitempty"`
+	Inode             uint64  `json:"inode,omitempty"`
+	Hash              string  `json:"hash,omitempty"`
+	QuickHash         string  `json:"quickHash,omitempty"`
+	WorldWritable     bool    `json:"worldWritable,omitempty"`
+	BrokenSymlink     bool    `json:"brokenSymlink,omitempty"`
+
+	// IsDuplicateLink is set on a symlink, or the file it points to, when
+	// the same file was already counted through another path; its size is
+	// left out of ScannedSize
+	IsDuplicateLink bool `json:"isDuplicateLink,omitempty"`
+
+	// InvalidName is set when the name is not valid UTF-8. EscapedPath then
+	// holds the path with invalid bytes escaped as \xNN, since JSON encoding
+	// replaces them.
+	InvalidName bool   `json:"invalidName,omitempty"`
+	EscapedPath string `json:"escapedPath,omitempty"`
+
+	// SuspiciousName is set when the name contains control characters, such
+	// as newlines, or path separators
+	SuspiciousName bool `json:"suspiciousName,omitempty"`
+
+	// RealPath is the path with every symlink resolved, set with
+	// ResolveRealPaths
+	RealPath string `json:"realPath,omitempty"`
+
+	// Preview holds the opening bytes of a text file as valid UTF-8
+	Preview string `json:"preview,omitempty"`
+
+	// QuarantinedTo is where a blocked file was moved to; in a dry run,
+	// WouldQuarantineTo is where it would have been moved instead
+	QuarantinedTo     string `json:"quarantinedTo,omitempty"`
+	WouldQuarantineTo string `json:"wouldQuarantineTo,omitempty"`
+
+	// ChangeType compares the file with the previous run when StatePath is
+	// set: added, modified or unchanged
+	ChangeType string `json:"changeType,omitempty"`
+
+	// MatchedRuleGroups names the rule groups the file matched
+	MatchedRuleGroups []string `json:"matchedRuleGroups,omitempty"`
+
+	// MatchedKeywords lists the Keywords found in the file's content
+	MatchedKeywords []string `json:"matchedKeywords,omitempty"`
+
+	Note string `json:"note,omitempty"`
 }
 
-// ScanConfig holds configuration for the file system scanner
+// ScanConfig holds configuration for the file system scanner.
+//
+// Fields naming files or directories on the scanning machine are tagged
+// json:"-", so a config decoded from an API request can never make the
+// server read, write, move or remove files of its choosing. The CLI and
+// library callers set them directly.
 type ScanConfig struct {
-	MaxFileSizeMB       int      `json:"maxFileSizeMB"`
-	AllowedTypes        []string `json:"allowedTypes"`
+	MaxFileSizeMB int `json:"maxFileSizeMB"`
+
+	// AllowedTypes lists the allowed extensions, matched case-insensitively.
+	// Each may be given with or without the leading dot: ".txt" and "txt"
+	// are equivalent.
+	AllowedTypes []string `json:"allowedTypes"`
+
 	BlockedPatterns     []string `json:"blockedPatterns"`
 	ScanRecursively     bool     `json:"scanRecursively"`
 	ExportBlockedToJSON bool     `json:"exportBlockedToJSON"`
 	WorkerCount         int      `json:"workerCount"`
 	BufferSize          int      `json:"bufferSize"`
+	WorkBufferSize      int      `json:"workBufferSize"`
+	ResultBufferSize    int      `json:"resultBufferSize"`
+	MaxOpenFiles        int      `json:"maxOpenFiles"`
+	IOConcurrency       int      `json:"ioConcurrency"`
+
+	// StreamToFile names a file results are written to as NDJSON instead of
+	// keeping them in memory; only blocked files are still kept for
+	// ExportBlockedToJSON. StreamCRLF ends its lines with \r\n instead of
+	// \n.
+	StreamToFile string `json:"-"`
+	StreamCRLF   bool   `json:"-"`
+
+	FlagExtensionMismatch  bool `json:"flagExtensionMismatch"`
+	BlockExtensionMismatch bool `json:"blockExtensionMismatch"`
+	ComputeEntropy         bool `json:"computeEntropy"`
+
+	// OmitBlocked leaves blocked files out of the result and out of the
+	// StreamToFile output to keep both small. They are still counted in
+	// the progress and, with ExportBlockedToJSON, written to the export.
+	OmitBlocked bool `json:"omitBlocked"`
+
+	DetectHardLinks         bool  `json:"detectHardLinks"`
+	MaxPathLength           int   `json:"maxPathLength"`
+	MaxNameLength           int   `json:"maxNameLength"`
+	ReportSlowestFiles      int   `json:"reportSlowestFiles"`
+	MaxDirEntries           int   `json:"maxDirEntries"`
+	PreviewBytes            int   `json:"previewBytes"`
+	MaxReadBytesPerSecond   int64 `json:"maxReadBytesPerSecond"`
+	MaxReadBytes            int64 `json:"maxReadBytes"`
+	CaseInsensitivePatterns bool  `json:"caseInsensitivePatterns"`
+	ComputeHash             bool  `json:"computeHash"`
+	QuickHash               bool  `json:"quickHash"`
+	FailOnError             bool  `json:"failOnError"`
+	BlockSuspiciousNames    bool  `json:"blockSuspiciousNames"`
+	CollectWorkerStats      bool  `json:"collectWorkerStats"`
+	PruneEmptyDirs          bool  `json:"pruneEmptyDirs"`
+	FollowSymlinkDirs       bool  `json:"followSymlinkDirs"`
+	FollowSymlinkFiles      bool  `json:"followSymlinkFiles"`
+	RelativePaths           bool  `json:"relativePaths"`
+	BlockEmptyFiles         bool  `json:"blockEmptyFiles"`
+	SortResults             bool  `json:"sortResults"`
+	FlagWorldWritable       bool  `json:"flagWorldWritable"`
+	BlockWorldWritable      bool  `json:"blockWorldWritable"`
+	RequireDirRoot          bool  `json:"requireDirRoot"`
+	AggregateDirSizes       bool  `json:"aggregateDirSizes"`
+	ResolveRealPaths        bool  `json:"resolveRealPaths"`
+
+	// PreferContentType derives FileType from the detected content instead
+	// of the extension when the two disagree, so a PNG named .txt reports
+	// "png". By default the extension wins.
+	PreferContentType bool `json:"preferContentType"`
+
+	// MaxPerExtension samples large trees: once this many files with the
+	// same extension have been processed, further ones are left out of the
+	// result and counted in SampledOut. Extensionless files share a single
+	// bucket. 0 means no limit. The kept files are the first ones processed,
+	// which in parallel mode depends on worker scheduling; use sequential
+	// traversal for a repeatable sample.
+	MaxPerExtension int `json:"maxPerExtension"`
+
+	// ProgressInterval is the minimum time between progress updates, both
+	// the LastUpdated stamp and calls to OnProgress; 0 means 250ms
+	ProgressInterval time.Duration `json:"progressInterval"`
+
+	// CancelTimeout bounds how long a cancelled scan waits for its workers,
+	// or the walk in sequential mode, to exit. Workers stuck in a slow
+	// system call are left behind, their results are dropped and the
+	// partial result is returned; 0 means 5s.
+	CancelTimeout time.Duration `json:"cancelTimeout"`
+
+	// HashAlgorithm selects the algorithm behind Hash and QuickHash, one of
+	// the Hash constants; empty means SHA-256
+	HashAlgorithm string `json:"hashAlgorithm,omitempty"`
+
+	// TraversalMode selects how the tree is walked; empty means parallel
+	TraversalMode string `json:"traversalMode,omitempty"`
+
+	// QuarantineDir names a directory blocked files are moved into, keeping
+	// their path below the scanned directory. It must be on the same file
+	// system. Unless DryRun is explicitly false, nothing is moved; the
+	// scanner only records where each file would go.
+	QuarantineDir string `json:"-"`
+	DryRun        *bool  `json:"-"`
+
+	// SFTPKeyPath names the private key offered to sftp:// roots, next to a
+	// password given in the URL. SFTPKnownHostsPath names the known_hosts
+	// file their host keys are verified against; empty means
+	// ~/.ssh/known_hosts.
+	SFTPKeyPath        string `json:"-"`
+	SFTPKnownHostsPath string `json:"-"`
+
+	// StatePath names a file where the size and modification time of every
+	// file are kept between runs. Each scan compares files with it, sets
+	// their ChangeType, lists the files it no longer finds in DeletedFiles
+	// and replaces it once the scan finishes. Files the scan skips, such as
+	// those below resumed or oversized directories or left out by sampling,
+	// keep their previous state.
+	StatePath string `json:"-"`
+
+	// DecisionLogPath names a file where the verdict on every scanned file,
+	// allowed or blocked, is written as NDJSON together with the rule behind
+	// it
+	DecisionLogPath string `json:"-"`
+
+	// IncrementalExportPath names a file that blocked files are appended to
+	// as NDJSON as soon as they are found, so a crash mid-scan still leaves
+	// a usable partial export. A summary line is appended when the scan ends.
+	IncrementalExportPath string `json:"-"`
+
+	// ExportKeyStyle selects the key casing of blocked_files.json: "camel",
+	// the default, or "snake"
+	ExportKeyStyle string `json:"exportKeyStyle,omitempty"`
+
+	// HashBlocklistPath names a file of SHA-256 hashes, one per line. Files
+	// whose content hashes into it are blocked; setting it enables hashing.
+	HashBlocklistPath string `json:"-"`
+
+	// CheckpointPath names a file where completed directories are recorded
+	// during the scan. With ResumeFromCheckpoint set, directories recorded
+	// by an interrupted scan are skipped. The file is removed once a scan
+	// finishes.
+	CheckpointPath       string `json:"-"`
+	ResumeFromCheckpoint bool   `json:"-"`
+
+	// FileTypeNames maps extensions such as ".tar.gz" to friendly type names
+	FileTypeNames map[string]string `json:"fileTypeNames,omitempty"`
+
+	// Categories maps extensions to categories, overriding CategoryForMime
+	Categories map[string]string `json:"categories,omitempty"`
+
+	// TextExtensions lists extensions, such as ".conf", whose files are
+	// previewed and searched for Keywords as text even when their content
+	// sniffs as binary
+	TextExtensions []string `json:"textExtensions,omitempty"`
+
+	// Keywords are searched for, ignoring case, in the content of text
+	// files within MaxFileSizeMB; the ones found are listed in
+	// MatchedKeywords. Files are only reported, not blocked.
+	Keywords []string `json:"keywords,omitempty"`
+
+	// TrustExtensionFor lists extensions, such as ".mp4", whose MIME type is
+	// taken from the extension instead of sniffed. Their files are not
+	// opened unless a hash is computed, so they get no entropy, preview or
+	// extension mismatch check.
+	TrustExtensionFor []string `json:"trustExtensionFor,omitempty"`
+
+	// AllowlistPaths are globs matched against the path relative to the scan
+	// root; matching files are never blocked
+	AllowlistPaths []string `json:"allowlistPaths,omitempty"`
+
+	// Rules are evaluated after the built-in checks
+	Rules []BlockRule `json:"-"`
+
+	// RuleGroups are named sets of patterns and rules evaluated after Rules.
+	// A file matching a group is blocked and the group is recorded in
+	// MatchedRuleGroups. By default evaluation stops at the first block;
+	// with EvaluateAllRuleGroups set every group is checked, even for files
+	// already blocked, so MatchedRuleGroups lists all of them.
+	RuleGroups            []RuleGroup `json:"ruleGroups,omitempty"`
+	EvaluateAllRuleGroups bool        `json:"evaluateAllRuleGroups"`
+
+	// ShouldProcess, when set, is consulted for every directory entry below
+	// the scan root before it is queued or descended into. Entries for which
+	// it returns false are skipped entirely.
+	ShouldProcess func(path string, info fs.FileInfo) bool `json:"-"`
+
+	// OnFile, when set, is called once for every entry of the result,
+	// including blocked and omitted files, as soon as it is processed. It
+	// runs synchronously on the single collector goroutine, so it needs no
+	// locking, but it stalls the scan for as long as it blocks.
+	OnFile func(file FileInfo) `json:"-"`
+
+	// OnProgress, when set, is called with a snapshot of the progress at
+	// most once per ProgressInterval. Like OnFile, it runs on the collector
+	// goroutine.
+	OnProgress func(progress ScanProgress) `json:"-"`
+}
+
+// Entry types for FileInfo.EntryType, derived from the fs.FileMode type bits
+const (
+	EntryFile    = "file"
+	EntryDir     = "dir"
+	EntrySymlink = "symlink"
+	EntryDevice  = "device"
+	EntrySocket  = "socket"
+	EntryPipe    = "pipe"
+	EntryOther   = "other"
+)
+
+// Traversal modes for ScanConfig.TraversalMode
+const (
+	// TraversalParallel walks directories concurrently and processes files
+	// on a pool of WorkerCount workers
+	TraversalParallel = "parallel"
+	// TraversalSequential walks the tree with fs.WalkDir, depth-first in
+	// lexical order, and processes every file on the calling goroutine
+	TraversalSequential = "sequential"
+)
+
+// Hash algorithms for ScanConfig.HashAlgorithm
+const (
+	HashSHA256 = "sha256"
+	HashSHA1   = "sha1"
+	HashMD5    = "md5"
+	HashBLAKE3 = "blake3"
+)
+
+// BlockRule is a custom blocking policy evaluated for every scanned file
+type BlockRule interface {
+	Evaluate(file *FileInfo) (blocked bool, reason string)
+}
+
+// RuleGroup is a named set of blocking rules. A file matches the group when
+// its name matches one of BlockedPatterns or one of Rules blocks it.
+type RuleGroup struct {
+	Name            string      `json:"name"`
+	BlockedPatterns []string    `json:"blockedPatterns,omitempty"`
+	Rules           []BlockRule `json:"-"`
+}
+
+// BlockRuleFunc adapts an ordinary function to the BlockRule interface
+type BlockRuleFunc func(file *FileInfo) (bool, string)
+
+// Evaluate calls f(file)
+func (f BlockRuleFunc) Evaluate(file *FileInfo) (bool, string) {
+	return f(file)
 }
 
 // ScanProgress represents the current progress of a scan operation
@@ -35,10 +328,50 @@ type ScanProgress struct {
 	TotalSize        int64     `json:"totalSize"`
 	ScannedSize      int64     `json:"scannedSize"`
 	BlockedFiles     int64     `json:"blockedFiles"`
+	BlockedSize      int64     `json:"blockedSize"`
+	VanishedFiles    int64     `json:"vanishedFiles"`
+	EmptyFiles       int64     `json:"emptyFiles"`
+	SymlinkCount     int64     `json:"symlinkCount"`
+	BrokenSymlinks   int64     `json:"brokenSymlinks"`
+	DeviceCount      int64     `json:"deviceCount"`
+	SocketCount      int64     `json:"socketCount"`
+	PipeCount        int64     `json:"pipeCount"`
 	Errors           []string  `json:"errors"`
 	StartTime        time.Time `json:"startTime"`
 	LastUpdated      time.Time `json:"lastUpdated"`
 	CurrentDirectory string    `json:"currentDirectory"`
+	Paused           bool      `json:"paused"`
+
+	// PermissionDenied counts the files and directories that could not be
+	// read for lack of permission. These errors are kept out of Errors so
+	// they do not drown out other problems.
+	PermissionDenied int64 `json:"permissionDenied"`
+
+	// BytesRead counts the file content read for detection and hashing.
+	// ReadBudgetExhausted is set once it reached MaxReadBytes; files found
+	// after that are recorded from their metadata only.
+	BytesRead           int64 `json:"bytesRead"`
+	ReadBudgetExhausted bool  `json:"readBudgetExhausted,omitempty"`
+
+	// ModifiedDuringScan counts files changed after the scan started; when
+	// it is non-zero the result may not be a consistent snapshot
+	ModifiedDuringScan int64 `json:"modifiedDuringScan"`
+
+	// SampledOut counts files skipped because MaxPerExtension files of
+	// their extension had already been processed. They are still part of
+	// TotalFiles and TotalSize, which count every file discovered.
+	SampledOut int64 `json:"sampledOut"`
+
+	// Channel occupancy sampled by GetProgress, useful to diagnose backpressure
+	WorkQueueDepth   int `json:"workQueueDepth"`
+	ResultQueueDepth int `json:"resultQueueDepth"`
+}
+
+// HardLinkGroup lists the paths found for a file with more than one link
+type HardLinkGroup struct {
+	Device uint64   `json:"device"`
+	Inode  uint64   `json:"inode"`
+	Paths  []string `json:"paths"`
 }
 
 // ScanResult contains the final results of a scan operation
@@ -48,11 +381,75 @@ type ScanResult struct {
 	Duration time.Duration `json:"duration"`
 	Success  bool          `json:"success"`
 	Error    string        `json:"error,omitempty"`
+
+	// DurationHuman is Duration formatted like "1.5s" for readers of the
+	// JSON; Duration keeps the exact nanosecond count
+	DurationHuman string `json:"durationHuman,omitempty"`
+
+	// HardLinks groups paths sharing a device and inode; UniqueSize counts
+	// each file once
+	HardLinks  []HardLinkGroup `json:"hardLinks,omitempty"`
+	UniqueSize int64           `json:"uniqueSize,omitempty"`
+
+	PerWorkerStats []WorkerStat `json:"perWorkerStats,omitempty"`
+
+	// Partial is set when a cancelled scan returned before all of its
+	// workers exited; files still being processed are missing
+	Partial bool `json:"partial,omitempty"`
+
+	// DeletedFiles lists the files below the scanned roots that the
+	// previous run recorded in StatePath and this run no longer found,
+	// leaving out the ones it skipped
+	DeletedFiles []string `json:"deletedFiles,omitempty"`
+
+	// HashAlgorithm names the algorithm behind the file hashes, when any
+	// were computed
+	HashAlgorithm string `json:"hashAlgorithm,omitempty"`
+
+	// Root is the absolute directory file paths are relative to when the
+	// scan used RelativePaths
+	Root string `json:"root,omitempty"`
+
+	// Tree shape: the deepest entry below a root, counting a root's
+	// children as depth 1, and the directory with the most entries
+	MaxDepthSeen   int    `json:"maxDepthSeen"`
+	DeepestPath    string `json:"deepestPath,omitempty"`
+	WidestDir      string `json:"widestDir,omitempty"`
+	WidestDirCount int    `json:"widestDirCount"`
+
+	// CategoryStats counts the files in each category
+	CategoryStats map[string]int64 `json:"categoryStats,omitempty"`
+
+	// AgeBuckets counts files by the age of their modification time at the
+	// start of the scan, keyed by the Age constants
+	AgeBuckets map[string]int64 `json:"ageBuckets,omitempty"`
+
+	// BlockedByReason lists the paths of blocked files by the reason they
+	// were blocked, without per-file details such as sizes or names
+	BlockedByReason map[string][]string `json:"blockedByReason,omitempty"`
+
+	// SlowestFiles lists the files that took longest to process, slowest
+	// first, when ReportSlowestFiles is set
+	SlowestFiles []FileTiming `json:"slowestFiles,omitempty"`
+}
+
+// FileTiming records how long processing a single file took
+type FileTiming struct {
+	Path     string        `json:"path"`
+	Duration time.Duration `json:"duration"`
+}
+
+// WorkerStat counts the files and bytes processed by one worker
+type WorkerStat struct {
+	Worker int   `json:"worker"`
+	Files  int64 `json:"files"`
+	Bytes  int64 `json:"bytes"`
 }
 
 // ScanWork represents a unit of work for the scanner
 type ScanWork struct {
 	Path     string
+	Root     string
 	IsDir    bool
 	Priority int
 }
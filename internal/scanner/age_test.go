@@ -0,0 +1,92 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestAgeBuckets(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Now()
+
+	testFiles := []struct {
+		name string
+		age  time.Duration
+	}{
+		{name: "today.txt", age: time.Hour},
+		{name: "yesterday.txt", age: 36 * time.Hour},
+		{name: "last-week.txt", age: 10 * 24 * time.Hour},
+		{name: "last-month.txt", age: 60 * 24 * time.Hour},
+		{name: "half-year.txt", age: 180 * 24 * time.Hour},
+		{name: "ancient.txt", age: 3 * 365 * 24 * time.Hour},
+	}
+	for _, tf := range testFiles {
+		path := filepath.Join(tempDir, tf.name)
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", tf.name, err)
+		}
+		modTime := now.Add(-tf.age)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to backdate %s: %v", tf.name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := map[string]int64{
+		models.AgeDay:   1,
+		models.AgeWeek:  1,
+		models.AgeMonth: 1,
+		models.AgeYear:  2,
+		models.AgeOlder: 1,
+	}
+	for bucket, count := range expected {
+		if result.AgeBuckets[bucket] != count {
+			t.Errorf("Expected %d files in bucket %s, got %d", count, bucket, result.AgeBuckets[bucket])
+		}
+	}
+	if len(result.AgeBuckets) != len(expected) {
+		t.Errorf("Expected only the buckets %v, got %v", expected, result.AgeBuckets)
+	}
+}
+
+func TestModifiedDuringScan(t *testing.T) {
+	tempDir := t.TempDir()
+	oldPath := filepath.Join(tempDir, "old.txt")
+	if err := os.WriteFile(oldPath, []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	modTime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(oldPath, modTime, modTime); err != nil {
+		t.Fatalf("Failed to backdate test file: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+	// Vertraag de scan en schrijf een nieuw bestand terwijl hij loopt
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "readdir" && name == tempDir {
+			time.Sleep(20 * time.Millisecond)
+			if err := os.WriteFile(filepath.Join(tempDir, "new.txt"), []byte("racing"), 0644); err != nil {
+				t.Errorf("Failed to create file during the scan: %v", err)
+			}
+		}
+		return nil
+	}}
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if result.Progress.ModifiedDuringScan != 1 {
+		t.Errorf("Expected 1 file modified during the scan, got %d", result.Progress.ModifiedDuringScan)
+	}
+}
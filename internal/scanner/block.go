@@ -0,0 +1,225 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"filesystem-logger/internal/models"
+)
+
+// evaluateBlock runs the built-in checks followed by the custom rules and
+// returns whether the file is blocked together with the reason of the first
+// check that fired
+func (s *Scanner) evaluateBlock(file *models.FileInfo) (blocked bool, reason string) {
+	blocked, reason, _ = s.evaluateRules(file)
+	return blocked, reason
+}
+
+// evaluateRules is evaluateBlock that also names the rule that fired, such
+// as "size" or "pattern:*.exe", for the decision log. The rule groups are
+// evaluated last.
+func (s *Scanner) evaluateRules(file *models.FileInfo) (blocked bool, reason, rule string) {
+	blocked, reason, rule = s.evaluateChecks(file)
+	return s.evaluateRuleGroups(file, blocked, reason, rule)
+}
+
+// evaluateChecks runs the built-in checks and the custom rules, stopping at
+// the first that blocks the file
+func (s *Scanner) evaluateChecks(file *models.FileInfo) (blocked bool, reason, rule string) {
+	// Check path length
+	if s.isPathTooLong(file.Path) {
+		return true, fmt.Sprintf("File path too long: %d > %d characters",
+			len(file.Path), s.config.MaxPathLength), "path-length"
+	}
+
+	// Check file name length
+	if s.config.MaxNameLength > 0 && len(file.Name) > s.config.MaxNameLength {
+		return true, fmt.Sprintf("Filename too long: %d > %d characters",
+			len(file.Name), s.config.MaxNameLength), "name-length"
+	}
+
+	// Check file size
+	if !s.isFileSizeAllowed(file.Size) {
+		return true, fmt.Sprintf("File size exceeds limit: %d bytes > %d MB",
+			file.Size, s.config.MaxFileSizeMB), "size"
+	}
+
+	// Check empty files
+	if s.config.BlockEmptyFiles && file.IsEmpty {
+		return true, "File is empty", "empty"
+	}
+
+	// Check if file type is allowed
+	if len(s.config.AllowedTypes) > 0 && !s.isTypeAllowed(file.Extension) {
+		return true, fmt.Sprintf("File type not allowed: %s", describeType(file)), "allowed-types"
+	}
+
+	// Check blocked patterns
+	for _, pattern := range s.config.BlockedPatterns {
+		matched, err := filepath.Match(s.foldCase(pattern), s.foldCase(file.Name))
+		if err == nil && matched {
+			return true, fmt.Sprintf("File matches blocked pattern: %s (%s)", pattern, file.Name), "pattern:" + pattern
+		}
+	}
+
+	// Check names with control characters or separators
+	if s.config.BlockSuspiciousNames && file.SuspiciousName {
+		return true, fmt.Sprintf("File name contains control characters or separators: %q", file.Name), "suspicious-name"
+	}
+
+	// Check extension spoofing
+	if s.config.BlockExtensionMismatch && file.ExtensionMismatch {
+		return true, fmt.Sprintf("File content does not match extension: %s",
+			describeType(file)), "extension-mismatch"
+	}
+
+	// Check world-writable files
+	if s.config.BlockWorldWritable && file.WorldWritable {
+		return true, "world-writable", "world-writable"
+	}
+
+	// Check known-bad hashes
+	if _, bad := s.badHashes[file.Hash]; bad && file.Hash != "" {
+		return true, "matches known-bad hash", "hash-blocklist"
+	}
+
+	// Check custom rules
+	for i, rule := range s.rules {
+		if blocked, reason := rule.Evaluate(file); blocked {
+			return true, reason, fmt.Sprintf("custom:%d", i)
+		}
+	}
+
+	return false, "", ""
+}
+
+// judge evaluates the block rules for file, lets the allowlist override a
+// block and logs the decision
+func (s *Scanner) judge(file *models.FileInfo, root string) {
+	var rule, allowedBy string
+	file.IsBlocked, file.BlockReason, rule = s.evaluateRules(file)
+	if file.IsBlocked {
+		allowedBy = s.applyAllowlist(file, root)
+	}
+	s.logDecision(file, rule, allowedBy)
+}
+
+// applyAllowlist unblocks a file whose path relative to root matches one of
+// the AllowlistPaths globs, keeping the overridden reason as a note. It
+// returns the matching glob, if any.
+func (s *Scanner) applyAllowlist(file *models.FileInfo, root string) string {
+	if len(s.config.AllowlistPaths) == 0 {
+		return ""
+	}
+
+	rel := relativePath(root, file.Path)
+	for _, pattern := range s.config.AllowlistPaths {
+		matched, err := path.Match(s.foldCase(filepath.ToSlash(pattern)), s.foldCase(rel))
+		if err == nil && matched {
+			file.Note = fmt.Sprintf("Allowed by allowlist %s despite: %s", pattern, file.BlockReason)
+			file.IsBlocked = false
+			file.BlockReason = ""
+			return pattern
+		}
+	}
+	return ""
+}
+
+// foldCase lowercases glob patterns and the names they are matched against
+// when CaseInsensitivePatterns is set
+func (s *Scanner) foldCase(value string) string {
+	if s.config.CaseInsensitivePatterns {
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+// relativePath returns p relative to root using forward slashes. A root
+// that is the file itself yields the file name.
+func relativePath(root, p string) string {
+	if root == p {
+		return filepath.Base(p)
+	}
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// isTypeAllowed looks up ext in the allowed types, ignoring case
+func (s *Scanner) isTypeAllowed(ext string) bool {
+	return s.allowedTypes[strings.ToLower(ext)]
+}
+
+// buildTypeSet lowercases the allowed types into a set so the per-file check
+// doesn't loop over the configuration. Types may be given with or without
+// the leading dot; the set always holds the dotted form, like Extension.
+func buildTypeSet(types []string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		set[normalizeExtension(t)] = true
+	}
+	return set
+}
+
+// normalizeExtension lowercases ext and adds the leading dot when missing
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// validatePatterns checks that every blocked pattern is a well-formed glob;
+// a malformed one would otherwise silently never match
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid blocked pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// validateAllowlist checks that every AllowlistPaths glob is well-formed,
+// as applyAllowlist matches them with forward slashes
+func validateAllowlist(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(filepath.ToSlash(pattern), ""); err != nil {
+			return fmt.Errorf("invalid allowlist pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (s *Scanner) isPathTooLong(path string) bool {
+	return s.config.MaxPathLength > 0 && len(path) > s.config.MaxPathLength
+}
+
+func (s *Scanner) isFileSizeAllowed(size int64) bool {
+	return size <= int64(s.config.MaxFileSizeMB)*1024*1024
+}
+
+// describeType formats the extension and detected MIME type of a file
+func describeType(file *models.FileInfo) string {
+	ext := file.Extension
+	if ext == "" {
+		ext = "no extension"
+	}
+	if file.MimeType == "" {
+		return ext
+	}
+	return fmt.Sprintf("%s (%s)", ext, file.MimeType)
+}
+
+func lowerKeys(m map[string]string) map[string]string {
+	lowered := make(map[string]string, len(m))
+	for k, v := range m {
+		lowered[strings.ToLower(k)] = v
+	}
+	return lowered
+}
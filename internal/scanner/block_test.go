@@ -0,0 +1,422 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestBlockReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   models.ScanConfig
+		file     models.FileInfo
+		contains []string
+	}{
+		{
+			name:     "Size limit",
+			config:   models.ScanConfig{MaxFileSizeMB: 1},
+			file:     models.FileInfo{Name: "big.bin", Extension: ".bin", Size: 2 * 1024 * 1024},
+			contains: []string{"2097152", "1 MB"},
+		},
+		{
+			name:     "Type not allowed",
+			config:   models.ScanConfig{MaxFileSizeMB: 1, AllowedTypes: []string{".txt"}},
+			file:     models.FileInfo{Name: "photo.jpg", Extension: ".jpg", MimeType: "image/jpeg"},
+			contains: []string{".jpg", "image/jpeg"},
+		},
+		{
+			name:     "Blocked pattern",
+			config:   models.ScanConfig{MaxFileSizeMB: 1, BlockedPatterns: []string{"*.tmp"}},
+			file:     models.FileInfo{Name: "cache.tmp", Extension: ".tmp"},
+			contains: []string{"*.tmp", "cache.tmp"},
+		},
+		{
+			name:     "Extension mismatch",
+			config:   models.ScanConfig{MaxFileSizeMB: 1, BlockExtensionMismatch: true},
+			file:     models.FileInfo{Name: "a.jpg", Extension: ".jpg", MimeType: "text/plain", ExtensionMismatch: true},
+			contains: []string{".jpg", "text/plain"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(tt.config)
+			blocked, reason := scanner.evaluateBlock(&tt.file)
+			if !blocked {
+				t.Fatalf("Expected %s to be blocked", tt.file.Name)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(reason, want) {
+					t.Errorf("Expected reason %q to contain %q", reason, want)
+				}
+			}
+		})
+	}
+
+	t.Run("Allowed file", func(t *testing.T) {
+		scanner := New(models.ScanConfig{MaxFileSizeMB: 1})
+		file := models.FileInfo{Name: "ok.txt", Extension: ".txt", Size: 10}
+		if blocked, reason := scanner.evaluateBlock(&file); blocked || reason != "" {
+			t.Errorf("Expected file to be allowed, got blocked=%v reason=%q", blocked, reason)
+		}
+	})
+}
+
+func newPatternHeavyScanner() *Scanner {
+	patterns := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		patterns = append(patterns, fmt.Sprintf("*.ext%03d", i))
+	}
+	return New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		AllowedTypes:    []string{".txt", ".log", ".csv", ".md", ".json"},
+		BlockedPatterns: patterns,
+	})
+}
+
+// BenchmarkEvaluateBlock measures a single evaluation per file
+func BenchmarkEvaluateBlock(b *testing.B) {
+	scanner := newPatternHeavyScanner()
+	file := models.FileInfo{Name: "report.txt", Extension: ".txt", Size: 1024}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanner.evaluateBlock(&file)
+	}
+}
+
+func TestMaxPathLength(t *testing.T) {
+	tempDir := t.TempDir()
+
+	deepDir := tempDir
+	for i := 0; i < 10; i++ {
+		deepDir = filepath.Join(deepDir, "nested_directory")
+	}
+	if err := os.MkdirAll(deepDir, 0755); err != nil {
+		t.Fatalf("Failed to create nested directories: %v", err)
+	}
+
+	longPath := filepath.Join(deepDir, "deep.txt")
+	shortPath := filepath.Join(tempDir, "short.txt")
+	for _, path := range []string{longPath, shortPath} {
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", path, err)
+		}
+	}
+
+	limit := len(shortPath) + 10
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		ScanRecursively: true,
+		MaxPathLength:   limit,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		switch file.Path {
+		case longPath:
+			if !file.IsBlocked || !strings.Contains(file.BlockReason, "path too long") {
+				t.Errorf("Expected long path to be blocked with 'path too long', got %q", file.BlockReason)
+			}
+		case shortPath:
+			if file.IsBlocked {
+				t.Errorf("Expected short path to be allowed, got %q", file.BlockReason)
+			}
+		}
+	}
+}
+
+func TestMaxPathLengthUnreadable(t *testing.T) {
+	tempDir := t.TempDir()
+	longPath := filepath.Join(tempDir, strings.Repeat("n", 40)+".txt")
+	if err := os.WriteFile(longPath, []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		blocked   bool
+	}{
+		{name: "Blocked", blocked: true},
+		{name: "Allowlisted", allowlist: []string{"n*.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:  10,
+				MaxPathLength:  len(tempDir) + 10,
+				AllowlistPaths: tt.allowlist,
+			})
+			// Te lange paden kunnen vaak niet eens ge-stat worden
+			scanner.fs = &hookFS{before: func(op, name string) error {
+				if op == "stat" && name == longPath {
+					return errors.New("file name too long")
+				}
+				return nil
+			}}
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			for _, file := range result.Files {
+				if file.Path == longPath && file.IsBlocked != tt.blocked {
+					t.Errorf("Expected IsBlocked=%v, got reason %q and note %q", tt.blocked, file.BlockReason, file.Note)
+				}
+			}
+			if blocked := result.Progress.BlockedFiles == 1; blocked != tt.blocked {
+				t.Errorf("Expected the file to be counted as blocked only when blocked, got BlockedFiles=%d", result.Progress.BlockedFiles)
+			}
+		})
+	}
+}
+
+func TestMaxNameLength(t *testing.T) {
+	tempDir := t.TempDir()
+
+	longName := strings.Repeat("n", 40) + ".txt"
+	shortName := "short.txt"
+	for _, name := range []string{longName, shortName} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	// Het volledige pad is langer dan de limiet, alleen de naam telt
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, MaxNameLength: 20})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		switch file.Name {
+		case longName:
+			if !file.IsBlocked || !strings.Contains(file.BlockReason, "Filename too long") {
+				t.Errorf("Expected long name to be blocked with 'Filename too long', got %q", file.BlockReason)
+			}
+		case shortName:
+			if file.IsBlocked {
+				t.Errorf("Expected short name to be allowed, got %q", file.BlockReason)
+			}
+		}
+	}
+}
+
+func TestAllowlistPaths(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]int64{
+		"critical/db.dump": 2 * 1024 * 1024,
+		"other/db.dump":    2 * 1024 * 1024,
+	}
+	for name, size := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   1,
+		ScanRecursively: true,
+		AllowlistPaths:  []string{"critical/*.dump"},
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		if file.IsDirectory {
+			continue
+		}
+		switch filepath.Base(filepath.Dir(file.Path)) {
+		case "critical":
+			if file.IsBlocked || file.BlockReason != "" {
+				t.Errorf("Expected allowlisted file to be unblocked, got reason %q", file.BlockReason)
+			}
+			if !strings.Contains(file.Note, "critical/*.dump") {
+				t.Errorf("Expected allowlist note, got %q", file.Note)
+			}
+		case "other":
+			if !file.IsBlocked {
+				t.Error("Expected file outside the allowlist to stay blocked")
+			}
+		}
+	}
+	if result.Progress.BlockedFiles != 1 {
+		t.Errorf("Expected BlockedFiles=1, got %d", result.Progress.BlockedFiles)
+	}
+}
+
+func TestCaseInsensitivePatterns(t *testing.T) {
+	tests := []struct {
+		name            string
+		caseInsensitive bool
+		shouldBlock     bool
+	}{
+		{name: "Case-insensitive matching", caseInsensitive: true, shouldBlock: true},
+		{name: "Case-sensitive matching", caseInsensitive: false, shouldBlock: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:           1,
+				BlockedPatterns:         []string{"*.TMP"},
+				CaseInsensitivePatterns: tt.caseInsensitive,
+			})
+			file := models.FileInfo{Name: "file.tmp", Extension: ".tmp"}
+			if blocked, reason := scanner.evaluateBlock(&file); blocked != tt.shouldBlock {
+				t.Errorf("Expected blocked=%v for file.tmp with *.TMP, got %v (%q)", tt.shouldBlock, blocked, reason)
+			}
+		})
+	}
+}
+
+func TestBlockedByReason(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]int{
+		"big1.bin":  2 * 1024 * 1024,
+		"big2.bin":  3 * 1024 * 1024,
+		"cache.tmp": 10,
+		"old.tmp":   10,
+		"empty.txt": 0,
+		"fine.txt":  10,
+	}
+	for name, size := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   1,
+		BlockedPatterns: []string{"*.tmp"},
+		BlockEmptyFiles: true,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := map[string][]string{
+		"File size exceeds limit":      {filepath.Join(tempDir, "big1.bin"), filepath.Join(tempDir, "big2.bin")},
+		"File matches blocked pattern": {filepath.Join(tempDir, "cache.tmp"), filepath.Join(tempDir, "old.tmp")},
+		"File is empty":                {filepath.Join(tempDir, "empty.txt")},
+	}
+	if len(result.BlockedByReason) != len(expected) {
+		t.Errorf("Expected %d reason groups, got %v", len(expected), result.BlockedByReason)
+	}
+	for reason, paths := range expected {
+		if fmt.Sprint(result.BlockedByReason[reason]) != fmt.Sprint(paths) {
+			t.Errorf("Expected %s to group %v, got %v", reason, paths, result.BlockedByReason[reason])
+		}
+	}
+}
+
+func TestInvalidBlockedPattern(t *testing.T) {
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, BlockedPatterns: []string{"*.tmp", "["}})
+	_, err := scanner.Scan(t.TempDir())
+	if !errors.Is(err, filepath.ErrBadPattern) {
+		t.Fatalf("Expected ErrBadPattern for a malformed pattern, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"["`) {
+		t.Errorf("Expected the error to name the pattern, got %v", err)
+	}
+}
+
+func TestInvalidAllowlistPattern(t *testing.T) {
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, AllowlistPaths: []string{"vendor/*", "docs/[a-"}})
+	_, err := scanner.Scan(t.TempDir())
+	if !errors.Is(err, path.ErrBadPattern) {
+		t.Fatalf("Expected ErrBadPattern for a malformed allowlist pattern, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"docs/[a-"`) {
+		t.Errorf("Expected the error to name the pattern, got %v", err)
+	}
+}
+
+func FuzzShouldBlockFile(f *testing.F) {
+	seeds := []struct{ pattern, name string }{
+		{"*.tmp", "cache.tmp"},
+		{"*.tmp", "notes.txt"},
+		{"[", "["},
+		{"[a-", "a"},
+		{"\\", "x"},
+		{"file?.log", "file1.log"},
+		{"[!abc]*", "dump"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.pattern, seed.name)
+	}
+
+	f.Fuzz(func(t *testing.T, pattern, name string) {
+		scanner := New(models.ScanConfig{MaxFileSizeMB: 1, BlockedPatterns: []string{pattern}})
+		_, matchErr := filepath.Match(pattern, name)
+		if scanner.initErr != nil {
+			if !errors.Is(scanner.initErr, filepath.ErrBadPattern) {
+				t.Fatalf("Expected ErrBadPattern for %q, got %v", pattern, scanner.initErr)
+			}
+			return
+		}
+		// Een geaccepteerd patroon mag voor geen enkele naam een fout geven
+		if matchErr != nil {
+			t.Fatalf("Pattern %q was accepted but fails to match %q: %v", pattern, name, matchErr)
+		}
+
+		file := models.FileInfo{Name: name}
+		blocked, reason := scanner.evaluateBlock(&file)
+		matched, _ := filepath.Match(pattern, name)
+		if blocked != matched {
+			t.Errorf("Pattern %q on %q: blocked=%v, match=%v", pattern, name, blocked, matched)
+		}
+		if blocked != (reason != "") {
+			t.Errorf("Pattern %q on %q: blocked=%v with reason %q", pattern, name, blocked, reason)
+		}
+	})
+}
+
+func TestAllowedTypesDotForms(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+	}{
+		{name: "Dotted", allowed: []string{".txt"}},
+		{name: "Undotted", allowed: []string{"txt"}},
+		{name: "Mixed", allowed: []string{"md", ".TXT", " pdf "}},
+		{name: "Uppercase undotted", allowed: []string{"TXT", ".md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{MaxFileSizeMB: 1, AllowedTypes: tt.allowed})
+
+			txt := models.FileInfo{Name: "notes.txt", Extension: ".txt", MimeType: "text/plain"}
+			if blocked, reason := scanner.evaluateBlock(&txt); blocked {
+				t.Errorf("Expected notes.txt to be allowed by %v, got %q", tt.allowed, reason)
+			}
+			jpg := models.FileInfo{Name: "photo.jpg", Extension: ".jpg", MimeType: "image/jpeg"}
+			if blocked, _ := scanner.evaluateBlock(&jpg); !blocked {
+				t.Errorf("Expected photo.jpg to be blocked by %v", tt.allowed)
+			}
+		})
+	}
+}
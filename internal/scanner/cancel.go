@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+// awaitWorkers waits for workersDone. Once ctx is cancelled it waits at most
+// CancelTimeout more and reports false when the workers did not exit in
+// time, for example because one is blocked in a system call that does not
+// observe the cancellation.
+func (s *Scanner) awaitWorkers(ctx context.Context, workersDone <-chan struct{}) bool {
+	select {
+	case <-workersDone:
+		return true
+	case <-ctx.Done():
+	}
+
+	timer := time.NewTimer(s.config.CancelTimeout)
+	defer timer.Stop()
+	select {
+	case <-workersDone:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// abandonScan returns the partial result of a cancelled scan whose workers
+// did not exit in time. The collector is stopped first, so results of the
+// remaining workers are dropped: no quarantine moves, callbacks or writes
+// happen after Scan returns. The stream file and incremental export are
+// finalized here; the channels, checkpoint and decision log are still in
+// use by the remaining workers, so they are only closed once those exit.
+func (s *Scanner) abandonScan(stream *bufio.Writer, workersDone, resultDone, errorsDone <-chan struct{}) (*models.ScanResult, error) {
+	log.Printf("scanner: %d workers still running %v after cancel; returning a partial result",
+		atomic.LoadInt32(&s.activeWorkers), s.config.CancelTimeout)
+
+	// Waits for the result being collected, if any
+	s.collectMu.Lock()
+	s.abandoned = true
+	s.collectMu.Unlock()
+
+	if stream != nil {
+		if err := stream.Flush(); err != nil {
+			log.Printf("scanner: failed to write stream file: %v", err)
+		}
+	}
+	progress := s.GetProgress()
+	if err := s.incremental.finish(*progress, time.Since(progress.StartTime), false); err != nil {
+		log.Printf("scanner: %v", err)
+	}
+
+	go func() {
+		<-workersDone
+		close(s.resultChan)
+		close(s.errorChan)
+		<-resultDone
+		<-errorsDone
+		s.checkpoint.close(false)
+		s.decisions.close()
+	}()
+
+	result := s.GetPartialResult()
+	result.Partial = true
+	result.Success = false
+	result.Error = ErrCancelled.Error()
+	return result, ErrCancelled
+}
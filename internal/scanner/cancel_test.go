@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestCancelTimeout(t *testing.T) {
+	for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+		t.Run(mode, func(t *testing.T) {
+			testCancelTimeout(t, mode)
+		})
+	}
+}
+
+func testCancelTimeout(t *testing.T, mode string) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	createFiles(t, root, 5)
+	stuck := filepath.Join(root, "stuck.bin")
+	if err := os.WriteFile(stuck, []byte("never read"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	streamPath := filepath.Join(tempDir, "results.ndjson")
+
+	// Het openen van stuck.bin blokkeert tot de test hem loslaat
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var enterOnce, releaseOnce sync.Once
+	releaseStuck := func() { releaseOnce.Do(func() { close(release) }) }
+	t.Cleanup(releaseStuck)
+
+	var onFile int64
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB: 10,
+		WorkerCount:   2,
+		TraversalMode: mode,
+		CancelTimeout: 100 * time.Millisecond,
+		StreamToFile:  streamPath,
+		OnFile:        func(models.FileInfo) { atomic.AddInt64(&onFile, 1) },
+	})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" && name == stuck {
+			enterOnce.Do(func() { close(entered) })
+			<-release
+		}
+		return nil
+	}}
+
+	type scanOutcome struct {
+		result *models.ScanResult
+		err    error
+	}
+	done := make(chan scanOutcome, 1)
+	go func() {
+		result, err := scanner.Scan(root)
+		done <- scanOutcome{result, err}
+	}()
+
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scan never opened the blocking file")
+	}
+
+	start := time.Now()
+	scanner.Cancel()
+
+	var outcome scanOutcome
+	select {
+	case outcome = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scan did not return after cancel")
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected cancel to return within the timeout, took %v", elapsed)
+	}
+	if !errors.Is(outcome.err, ErrCancelled) {
+		t.Errorf("Expected ErrCancelled, got %v", outcome.err)
+	}
+	if outcome.result == nil || !outcome.result.Partial {
+		t.Fatalf("Expected a partial result, got %+v", outcome.result)
+	}
+
+	// The stream is complete when Scan returns and nothing follows once
+	// the stuck worker finishes
+	streamed, err := os.ReadFile(streamPath)
+	if err != nil {
+		t.Fatalf("Failed to read stream file: %v", err)
+	}
+	calls := atomic.LoadInt64(&onFile)
+
+	releaseStuck()
+	time.Sleep(100 * time.Millisecond)
+
+	if after, _ := os.ReadFile(streamPath); !bytes.Equal(after, streamed) {
+		t.Errorf("Expected the stream file to stay unchanged after Scan returned")
+	}
+	if after := atomic.LoadInt64(&onFile); after != calls {
+		t.Errorf("Expected no OnFile calls after Scan returned, got %d more", after-calls)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(streamed), []byte("\n"))
+	if int64(len(lines)) != calls {
+		t.Errorf("Expected a streamed line per collected file, got %d lines for %d files", len(lines), calls)
+	}
+	for _, line := range lines {
+		var file models.FileInfo
+		if err := json.Unmarshal(line, &file); err != nil {
+			t.Fatalf("Expected every streamed line to decode, got %q: %v", line, err)
+		}
+		if file.Path == stuck {
+			t.Error("Expected the stuck file to be missing from the stream")
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkpointInterval is the minimum time between checkpoint flushes
+var checkpointInterval = 2 * time.Second
+
+// checkpoint records directories whose whole subtree has been processed, so
+// an interrupted scan can be resumed without walking them again.
+//
+// Every walked directory holds a pending count: one for its own listing,
+// one per queued file and one per subdirectory walk. A directory completes
+// when the count drops to zero, which in turn releases its parent. A walk
+// that is cancelled or crashes midway never completes, so partially scanned
+// directories are walked again in full on resume. Directories that could not
+// be read completely are failed: they and every directory above them are
+// never recorded, so resuming tries them again.
+type checkpoint struct {
+	mu      sync.Mutex
+	done    map[string]bool
+	pending map[string]*pendingDir
+	file    *os.File
+	writer  *bufio.Writer
+	flushed time.Time
+	err     error
+	// owned is set when the file was created by this scan or held
+	// checkpoint entries, and may therefore be removed. A file that was
+	// empty is emptied again instead.
+	owned bool
+}
+
+// pendingDir is a directory whose subtree is still being processed
+type pendingDir struct {
+	holds  int
+	parent string
+	failed bool
+}
+
+// openCheckpoint opens the checkpoint at path. When resume is set the
+// directories completed by an earlier run are loaded and new ones are
+// appended; otherwise the file starts out empty. An existing file that is
+// not a checkpoint is left alone and reported as an error.
+func openCheckpoint(path string, resume bool) (*checkpoint, error) {
+	cp := &checkpoint{
+		done:    make(map[string]bool),
+		pending: make(map[string]*pendingDir),
+		flushed: time.Now(),
+	}
+
+	entries, err := cp.load(path)
+	if err != nil {
+		return nil, err
+	}
+	// Alleen eigen bestanden worden na afloop verwijderd
+	cp.owned = entries != 0
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !resume {
+		cp.done = make(map[string]bool)
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+	if entries < 0 {
+		flags |= os.O_EXCL
+	}
+
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint: %v", err)
+	}
+	cp.file = f
+	cp.writer = bufio.NewWriter(f)
+	return cp, nil
+}
+
+// load reads the completed directories, one JSON string per line, and
+// returns how many were read, or -1 when there is no checkpoint yet. A last
+// line cut short by a crash is ignored; that directory is simply walked
+// again. Any other line that is not a directory means path holds something
+// else, which is never overwritten.
+func (cp *checkpoint) load(path string) (int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+	defer f.Close()
+
+	entries := 0
+	lines := bufio.NewReader(f)
+	for {
+		line, err := lines.ReadBytes('\n')
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read checkpoint: %v", err)
+		}
+		var dir string
+		if json.Unmarshal(line, &dir) != nil {
+			return 0, fmt.Errorf("%s is not a checkpoint", path)
+		}
+		cp.done[dir] = true
+		entries++
+	}
+}
+
+// isDone reports whether the subtree of dir was completed by an earlier run
+func (cp *checkpoint) isDone(dir string) bool {
+	if cp == nil {
+		return false
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.done[dir]
+}
+
+// enter starts tracking dir, holding it open until its listing is
+// released. Parent is the directory whose walk found dir, or empty for a
+// root.
+func (cp *checkpoint) enter(dir, parent string) {
+	if cp == nil {
+		return
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.pending[dir] = &pendingDir{holds: 1, parent: parent}
+}
+
+// hold keeps dir open for one more file or subdirectory
+func (cp *checkpoint) hold(dir string) {
+	if cp == nil {
+		return
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if pending, ok := cp.pending[dir]; ok {
+		pending.holds++
+	}
+}
+
+// fail marks dir as not completely read; it is not recorded when released
+func (cp *checkpoint) fail(dir string) {
+	if cp == nil {
+		return
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if pending, ok := cp.pending[dir]; ok {
+		pending.failed = true
+	}
+}
+
+// release drops one hold on dir. A directory that is no longer held is
+// recorded as completed, unless it or a subdirectory failed, and releases
+// its parent. Untracked directories are ignored.
+func (cp *checkpoint) release(dir string) {
+	if cp == nil {
+		return
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	failed := false
+	for dir != "" {
+		pending, ok := cp.pending[dir]
+		if !ok {
+			return
+		}
+		// Een onvolledige subdirectory maakt ook de ouder onvolledig
+		pending.failed = pending.failed || failed
+		if pending.holds--; pending.holds > 0 {
+			return
+		}
+		delete(cp.pending, dir)
+		if !pending.failed {
+			cp.record(dir)
+		}
+		failed = pending.failed
+		dir = pending.parent
+	}
+}
+
+// record appends dir to the checkpoint and flushes it when the last flush
+// is long enough ago. Only the first write error is kept.
+func (cp *checkpoint) record(dir string) {
+	line, _ := json.Marshal(dir)
+	if _, err := cp.writer.Write(append(line, '\n')); err != nil && cp.err == nil {
+		cp.err = err
+	}
+	if time.Since(cp.flushed) >= checkpointInterval {
+		cp.flush()
+	}
+}
+
+func (cp *checkpoint) flush() {
+	if err := cp.writer.Flush(); err != nil && cp.err == nil {
+		cp.err = err
+	}
+	cp.flushed = time.Now()
+}
+
+// close flushes the checkpoint. When the scan finished, the checkpoint has
+// served its purpose and is removed so the next scan starts from scratch;
+// a file that already existed but was empty is only emptied.
+func (cp *checkpoint) close(finished bool) error {
+	if cp == nil {
+		return nil
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.flush()
+	if err := cp.file.Close(); err != nil && cp.err == nil {
+		cp.err = err
+	}
+	if cp.err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", cp.err)
+	}
+	if finished && cp.owned {
+		return os.Remove(cp.file.Name())
+	}
+	if finished {
+		return os.Truncate(cp.file.Name(), 0)
+	}
+	return nil
+}
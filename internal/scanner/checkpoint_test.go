@@ -0,0 +1,203 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	checkpointPath := filepath.Join(tempDir, "scan.checkpoint")
+
+	testFiles := []string{
+		"a/x.txt",
+		"a/deeper/w.txt",
+		"b/y.txt",
+		"b/z.txt",
+	}
+	for _, name := range testFiles {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	config := models.ScanConfig{
+		MaxFileSizeMB:   10,
+		ScanRecursively: true,
+		TraversalMode:   models.TraversalSequential,
+		CheckpointPath:  checkpointPath,
+	}
+
+	// Eerste scan: afbreken halverwege directory b
+	interrupted := New(config)
+	interrupted.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" && name == filepath.Join(root, "b", "y.txt") {
+			interrupted.Cancel()
+		}
+		return nil
+	}}
+	if _, err := interrupted.Scan(root); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Expected ErrCancelled, got %v", err)
+	}
+
+	data, err := os.ReadFile(checkpointPath)
+	if err != nil {
+		t.Fatalf("Expected the checkpoint to remain after an interrupted scan: %v", err)
+	}
+	checkpoint := string(data)
+	for _, dir := range []string{"a", filepath.Join("a", "deeper")} {
+		if !strings.Contains(checkpoint, `"`+filepath.Join(root, dir)+`"`) {
+			t.Errorf("Expected %s to be recorded as completed, got:\n%s", dir, checkpoint)
+		}
+	}
+	for _, dir := range []string{root, filepath.Join(root, "b")} {
+		if strings.Contains(checkpoint, `"`+dir+`"`) {
+			t.Errorf("Expected partially scanned %s not to be recorded, got:\n%s", dir, checkpoint)
+		}
+	}
+
+	// Tweede scan: hervatten vanaf het checkpoint
+	config.ResumeFromCheckpoint = true
+	config.TraversalMode = models.TraversalParallel
+	resumed := New(config)
+	var mu sync.Mutex
+	walked := make(map[string]bool)
+	resumed.fs = &hookFS{before: func(op, name string) error {
+		if op == "readdir" {
+			mu.Lock()
+			walked[name] = true
+			mu.Unlock()
+		}
+		return nil
+	}}
+	result, err := resumed.Scan(root)
+	if err != nil {
+		t.Fatalf("Resumed scan failed: %v", err)
+	}
+
+	for _, dir := range []string{"a", filepath.Join("a", "deeper")} {
+		if walked[filepath.Join(root, dir)] {
+			t.Errorf("Expected completed directory %s not to be walked again", dir)
+		}
+	}
+	if !walked[filepath.Join(root, "b")] {
+		t.Error("Expected the partially scanned directory b to be walked again")
+	}
+
+	files := make(map[string]bool)
+	for _, file := range result.Files {
+		files[file.Name] = true
+	}
+	for _, name := range []string{"y.txt", "z.txt"} {
+		if !files[name] {
+			t.Errorf("Expected %s in the resumed result", name)
+		}
+	}
+	for _, name := range []string{"x.txt", "w.txt"} {
+		if files[name] {
+			t.Errorf("Expected %s to be skipped in the resumed result", name)
+		}
+	}
+
+	if _, err := os.Stat(checkpointPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the checkpoint to be removed after a finished scan, got %v", err)
+	}
+}
+
+func TestCheckpointSkipsUnreadDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	checkpointPath := filepath.Join(tempDir, "scan.checkpoint")
+
+	for _, name := range []string{"a/x.txt", "a/locked/w.txt", "a/open/v.txt", "b/y.txt"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	// a/locked kan niet gelezen worden; de scan wordt in b afgebroken
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		ScanRecursively: true,
+		TraversalMode:   models.TraversalSequential,
+		CheckpointPath:  checkpointPath,
+	})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "readdir" && name == filepath.Join(root, "a", "locked") {
+			return os.ErrPermission
+		}
+		if op == "open" && name == filepath.Join(root, "b", "y.txt") {
+			scanner.Cancel()
+		}
+		return nil
+	}}
+	if _, err := scanner.Scan(root); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Expected ErrCancelled, got %v", err)
+	}
+
+	data, err := os.ReadFile(checkpointPath)
+	if err != nil {
+		t.Fatalf("Expected the checkpoint to remain after an interrupted scan: %v", err)
+	}
+	checkpoint := string(data)
+	if !strings.Contains(checkpoint, `"`+filepath.Join(root, "a", "open")+`"`) {
+		t.Errorf("Expected the readable directory a/open to be recorded, got:\n%s", checkpoint)
+	}
+	for _, dir := range []string{filepath.Join(root, "a", "locked"), filepath.Join(root, "a")} {
+		if strings.Contains(checkpoint, `"`+dir+`"`) {
+			t.Errorf("Expected %s not to be recorded after a read error, got:\n%s", dir, checkpoint)
+		}
+	}
+}
+
+func TestCheckpointKeepsOtherFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "x.txt"), []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	for _, resume := range []bool{false, true} {
+		other := filepath.Join(tempDir, "notes.txt")
+		if err := os.WriteFile(other, []byte("not a checkpoint\n"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		config := models.ScanConfig{MaxFileSizeMB: 10, CheckpointPath: other, ResumeFromCheckpoint: resume}
+		if _, err := New(config).Scan(root); err == nil {
+			t.Errorf("Expected an error using a file that is not a checkpoint (resume %v)", resume)
+		}
+		if data, err := os.ReadFile(other); err != nil || string(data) != "not a checkpoint\n" {
+			t.Errorf("Expected the file to be left alone (resume %v), got %q, %v", resume, data, err)
+		}
+	}
+
+	// Een bestaand leeg bestand wordt na afloop weer leeg, niet verwijderd
+	empty := filepath.Join(tempDir, "empty")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if _, err := New(models.ScanConfig{MaxFileSizeMB: 10, ScanRecursively: true, CheckpointPath: empty}).Scan(root); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if info, err := os.Stat(empty); err != nil || info.Size() != 0 {
+		t.Errorf("Expected the existing empty file to be kept empty, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package scanner
+
+import (
+	"bytes"
+	"io"
+)
+
+// crlfWriter turns every \n written through it into \r\n. JSON encodes
+// newlines inside strings as \n escapes, so only line ends are affected.
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,19 @@
+//go:build darwin || freebsd || netbsd
+
+package scanner
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// creationTime returns the birth time recorded by the filesystem, or the
+// zero time when it records none
+func creationTime(info os.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat.Birthtimespec.Sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(stat.Birthtimespec.Unix())
+}
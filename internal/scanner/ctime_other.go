@@ -0,0 +1,14 @@
+//go:build !darwin && !freebsd && !netbsd && !windows
+
+package scanner
+
+import (
+	"os"
+	"time"
+)
+
+// creationTime is not supported on this platform and always returns the
+// zero time
+func creationTime(info os.FileInfo) time.Time {
+	return time.Time{}
+}
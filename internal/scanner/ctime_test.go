@@ -0,0 +1,44 @@
+//go:build darwin || freebsd || netbsd || windows
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestCreationTime(t *testing.T) {
+	tempDir := t.TempDir()
+
+	path := filepath.Join(tempDir, "fresh.txt")
+	if err := os.WriteFile(path, []byte("new file"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	var file *models.FileInfo
+	for i := range result.Files {
+		if result.Files[i].Path == path {
+			file = &result.Files[i]
+		}
+	}
+	if file == nil {
+		t.Fatalf("Expected %s in the result", path)
+	}
+	if file.CreatedTime.IsZero() {
+		t.Skip("File system records no birth time")
+	}
+	// Het bestand is net aangemaakt
+	if age := time.Since(file.CreatedTime); age < -time.Minute || age > time.Minute {
+		t.Errorf("Expected a creation time close to now, got %v", file.CreatedTime)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package scanner
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// creationTime returns the creation time recorded by NTFS
+func creationTime(info os.FileInfo) time.Time {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(0, data.CreationTime.Nanoseconds())
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"filesystem-logger/internal/models"
+)
+
+// decisionLog writes the verdict on every evaluated file as NDJSON. Workers
+// record concurrently, so writes are serialized.
+type decisionLog struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	err     error
+}
+
+func openDecisionLog(path string) (*decisionLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decision log: %v", err)
+	}
+	writer := bufio.NewWriter(f)
+	return &decisionLog{file: f, writer: writer, encoder: json.NewEncoder(writer)}, nil
+}
+
+// record appends decision to the log. Only the first write error is kept.
+func (l *decisionLog) record(decision models.Decision) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.encoder.Encode(decision); err != nil && l.err == nil {
+		l.err = err
+	}
+}
+
+func (l *decisionLog) close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.writer.Flush(); err != nil && l.err == nil {
+		l.err = err
+	}
+	if err := l.file.Close(); err != nil && l.err == nil {
+		l.err = err
+	}
+	if l.err != nil {
+		return fmt.Errorf("failed to write decision log: %v", l.err)
+	}
+	return nil
+}
+
+// logDecision records the verdict on a file after the block rules and the
+// allowlist were applied
+func (s *Scanner) logDecision(file *models.FileInfo, rule, allowedBy string) {
+	if s.decisions == nil {
+		return
+	}
+	decision := models.Decision{Path: file.Path, Decision: models.DecisionAllow}
+	if s.relBase != "" {
+		decision.Path = s.resultPath(file.Path)
+	}
+	switch {
+	case file.IsBlocked:
+		decision.Decision = models.DecisionBlock
+		decision.Reason = file.BlockReason
+		decision.Rule = rule
+	case allowedBy != "":
+		decision.Reason = file.Note
+		decision.Rule = "allowlist:" + allowedBy
+	}
+	s.decisions.record(decision)
+}
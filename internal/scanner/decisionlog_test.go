@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestDecisionLog(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	logPath := filepath.Join(tempDir, "decisions.ndjson")
+
+	testFiles := []string{"notes.txt", "setup.exe", "vendor/tool.exe"}
+	for _, name := range testFiles {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		ScanRecursively: true,
+		BlockedPatterns: []string{"*.exe"},
+		AllowlistPaths:  []string{"vendor/*"},
+		DecisionLogPath: logPath,
+	})
+	if _, err := scanner.Scan(root); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	f, err := os.Open(logPath)
+	if err != nil {
+		t.Fatalf("Failed to open decision log: %v", err)
+	}
+	defer f.Close()
+
+	decisions := make(map[string]models.Decision)
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		var decision models.Decision
+		if err := json.Unmarshal(lines.Bytes(), &decision); err != nil {
+			t.Fatalf("Failed to decode %s: %v", lines.Text(), err)
+		}
+		rel, _ := filepath.Rel(root, decision.Path)
+		decisions[filepath.ToSlash(rel)] = decision
+	}
+
+	if len(decisions) != len(testFiles) {
+		t.Errorf("Expected a decision per file and none for directories, got %v", decisions)
+	}
+
+	tests := []struct {
+		path     string
+		decision string
+		rule     string
+	}{
+		{path: "notes.txt", decision: models.DecisionAllow, rule: ""},
+		{path: "setup.exe", decision: models.DecisionBlock, rule: "pattern:*.exe"},
+		{path: "vendor/tool.exe", decision: models.DecisionAllow, rule: "allowlist:vendor/*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := decisions[tt.path]
+			if !ok {
+				t.Fatalf("Expected a decision for %s", tt.path)
+			}
+			if got.Decision != tt.decision || got.Rule != tt.rule {
+				t.Errorf("Expected %s by rule %q, got %s by rule %q", tt.decision, tt.rule, got.Decision, got.Rule)
+			}
+			if tt.decision == models.DecisionBlock && got.Reason == "" {
+				t.Error("Expected the block reason to be logged")
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package scanner
+
+// uniqueRoots drops roots that would be scanned twice: repeated paths and,
+// in recursive mode, roots lying inside another root directory, whose
+// entries the walk of that directory already reports
+func (s *Scanner) uniqueRoots(roots []string) []string {
+	abs := make([]string, len(roots))
+	dirs := make(map[string]bool)
+	for i, root := range roots {
+		abs[i] = s.absPath(root)
+		if info, err := s.fs.Stat(root); err == nil && info.IsDir() {
+			dirs[abs[i]] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var unique []string
+	for i, root := range roots {
+		if seen[abs[i]] || s.coveredByRoot(abs[i], dirs) {
+			continue
+		}
+		seen[abs[i]] = true
+		unique = append(unique, root)
+	}
+	return unique
+}
+
+// coveredByRoot reports whether path lies below one of the root directories
+// and will therefore be reached by a recursive walk
+func (s *Scanner) coveredByRoot(path string, dirs map[string]bool) bool {
+	if !s.config.ScanRecursively || s.explicitPaths {
+		return false
+	}
+	for dir := range dirs {
+		if path != dir && isWithin(path, dir) {
+			return true
+		}
+	}
+	return false
+}
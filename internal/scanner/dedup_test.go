@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestNoDuplicateEntries(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := []string{
+		"top.txt",
+		"a/one.txt",
+		"a/b/two.txt",
+		"a/b/c/three.txt",
+		"d/four.txt",
+	}
+	for _, name := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		recursive bool
+		roots     []string
+		expected  int
+	}{
+		{name: "Recursive", recursive: true, roots: []string{tempDir}, expected: 10},
+		{name: "Non-recursive", recursive: false, roots: []string{tempDir}, expected: 4},
+		{
+			name:      "Recursive overlapping roots",
+			recursive: true,
+			roots:     []string{tempDir, filepath.Join(tempDir, "a"), filepath.Join(tempDir, "a", "b"), tempDir},
+			expected:  10,
+		},
+		{
+			// De root zelf plus top.txt, a en d, daarna a/one.txt en a/b
+			name:      "Non-recursive overlapping roots",
+			recursive: false,
+			roots:     []string{tempDir, filepath.Join(tempDir, "a"), tempDir},
+			expected:  6,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+			t.Run(tt.name+"/"+mode, func(t *testing.T) {
+				scanner := New(models.ScanConfig{
+					MaxFileSizeMB:   10,
+					ScanRecursively: tt.recursive,
+					TraversalMode:   mode,
+				})
+				result, err := scanner.scanRoots(tt.roots)
+				if err != nil {
+					t.Fatalf("Scan failed: %v", err)
+				}
+
+				seen := make(map[string]int)
+				for _, file := range result.Files {
+					seen[file.Path]++
+				}
+				for path, count := range seen {
+					if count > 1 {
+						t.Errorf("Expected %s once, got %d entries", path, count)
+					}
+				}
+				if len(result.Files) != tt.expected {
+					t.Errorf("Expected %d entries, got %d", tt.expected, len(result.Files))
+				}
+				if result.Progress.TotalFiles != int64(len(result.Files)) {
+					t.Errorf("Expected TotalFiles %d, got %d", len(result.Files), result.Progress.TotalFiles)
+				}
+			})
+		}
+	}
+}
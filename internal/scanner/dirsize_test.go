@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestAggregateDirSizes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]int{
+		"a.txt":              10,
+		"sub/b.txt":          20,
+		"sub/deep/c.txt":     30,
+		"sub/deep/d.txt":     5,
+		"other/e.txt":        100,
+		"sub/empty/.keep":    0,
+		"sub/deep/most/f.go": 1,
+	}
+	for name, size := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	expected := map[string]int64{
+		".":             166,
+		"sub":           56,
+		"sub/deep":      36,
+		"sub/deep/most": 1,
+		"sub/empty":     0,
+		"other":         100,
+	}
+
+	for _, relative := range []bool{false, true} {
+		scanner := New(models.ScanConfig{
+			MaxFileSizeMB:     10,
+			ScanRecursively:   true,
+			AggregateDirSizes: true,
+			RelativePaths:     relative,
+		})
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+
+		for _, file := range result.Files {
+			if !file.IsDirectory {
+				continue
+			}
+			path := file.Path
+			if !relative {
+				path, _ = filepath.Rel(tempDir, file.Path)
+			}
+			want, ok := expected[filepath.ToSlash(path)]
+			if !ok {
+				t.Errorf("Unexpected directory %s", file.Path)
+				continue
+			}
+			if file.Size != want {
+				t.Errorf("Expected %s (relative=%v) to total %d bytes, got %d", path, relative, want, file.Size)
+			}
+		}
+	}
+}
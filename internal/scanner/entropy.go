@@ -0,0 +1,28 @@
+package scanner
+
+import "math"
+
+// shannonEntropy returns the Shannon entropy of data in bits per byte,
+// ranging from 0 (constant data) to 8 (uniformly random data)
+func shannonEntropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+
+	var entropy float64
+	total := float64(len(data))
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / total
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestEntropy(t *testing.T) {
+	tempDir := t.TempDir()
+
+	random := make([]byte, 4096)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatalf("Failed to generate random data: %v", err)
+	}
+
+	testFiles := map[string][]byte{
+		"zeros.bin":  make([]byte, 4096),
+		"random.bin": random,
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, ComputeEntropy: true})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		switch file.Name {
+		case "zeros.bin":
+			if file.Entropy > 0.1 {
+				t.Errorf("Expected low entropy for zero-filled file, got %.2f", file.Entropy)
+			}
+		case "random.bin":
+			if file.Entropy < 7.0 {
+				t.Errorf("Expected high entropy for random file, got %.2f", file.Entropy)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package scanner
+
+import (
+	"io/fs"
+	"sync/atomic"
+
+	"filesystem-logger/internal/models"
+)
+
+// entryType classifies a file mode as one of the models.Entry* types
+func entryType(mode fs.FileMode) string {
+	switch {
+	case mode.IsRegular():
+		return models.EntryFile
+	case mode.IsDir():
+		return models.EntryDir
+	case mode&fs.ModeSymlink != 0:
+		return models.EntrySymlink
+	case mode&fs.ModeDevice != 0:
+		return models.EntryDevice
+	case mode&fs.ModeSocket != 0:
+		return models.EntrySocket
+	case mode&fs.ModeNamedPipe != 0:
+		return models.EntryPipe
+	default:
+		return models.EntryOther
+	}
+}
+
+// countSpecial updates the progress counter for devices, sockets and pipes
+func (s *Scanner) countSpecial(typ string) {
+	switch typ {
+	case models.EntryDevice:
+		atomic.AddInt64(&s.progress.DeviceCount, 1)
+	case models.EntrySocket:
+		atomic.AddInt64(&s.progress.SocketCount, 1)
+	case models.EntryPipe:
+		atomic.AddInt64(&s.progress.PipeCount, 1)
+	}
+}
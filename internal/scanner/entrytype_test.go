@@ -0,0 +1,80 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestEntryTypes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	fifo := filepath.Join(tempDir, "queue.fifo")
+	if err := syscall.Mkfifo(fifo, 0644); err != nil {
+		t.Skipf("Cannot create FIFO: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "plain.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Symlink("plain.txt", filepath.Join(tempDir, "link.txt")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	opened := false
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" && name == fifo {
+			opened = true
+		}
+		return nil
+	}}
+
+	// Een FIFO openen blokkeert tot er een schrijver is
+	done := make(chan struct{})
+	var result *models.ScanResult
+	var err error
+	go func() {
+		defer close(done)
+		result, err = scanner.Scan(tempDir)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scan blocked on the FIFO")
+	}
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if opened {
+		t.Error("Expected the FIFO not to be opened")
+	}
+	if result.Progress.PipeCount != 1 || result.Progress.SymlinkCount != 1 {
+		t.Errorf("Expected 1 pipe and 1 symlink, got %d pipes and %d symlinks",
+			result.Progress.PipeCount, result.Progress.SymlinkCount)
+	}
+	if result.Progress.DeviceCount != 0 || result.Progress.SocketCount != 0 {
+		t.Errorf("Expected no devices or sockets, got %+v", result.Progress)
+	}
+
+	expected := map[string]string{
+		filepath.Base(tempDir): models.EntryDir,
+		"queue.fifo":           models.EntryPipe,
+		"plain.txt":            models.EntryFile,
+		"link.txt":             models.EntrySymlink,
+	}
+	for _, file := range result.Files {
+		if file.EntryType != expected[file.Name] {
+			t.Errorf("%s: expected entry type %q, got %q", file.Name, expected[file.Name], file.EntryType)
+		}
+		if file.Name == "queue.fifo" && file.MimeType != "" {
+			t.Errorf("Expected no MIME type for the FIFO, got %q", file.MimeType)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// file is the subset of *os.File used by the scanner
+type file interface {
+	io.Reader
+	io.Seeker
+	io.Closer
+}
+
+// fileSystem abstracts the filesystem operations used by the scanner so
+// tests can inject failures and delays, and sftp:// roots can be walked
+// remotely
+type fileSystem interface {
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	ReadDir(name string) ([]os.DirEntry, error)
+	Open(name string) (file, error)
+	Readlink(name string) (string, error)
+	EvalSymlinks(name string) (string, error)
+}
+
+// osFS implements fileSystem using the os package
+type osFS struct{}
+
+func (osFS) Stat(name string) (os.FileInfo, error)      { return os.Stat(name) }
+func (osFS) Lstat(name string) (os.FileInfo, error)     { return os.Lstat(name) }
+func (osFS) ReadDir(name string) ([]os.DirEntry, error) { return os.ReadDir(name) }
+func (osFS) Readlink(name string) (string, error)       { return os.Readlink(name) }
+func (osFS) EvalSymlinks(name string) (string, error)   { return filepath.EvalSymlinks(name) }
+
+func (osFS) Open(name string) (file, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,46 @@
+package scanner
+
+import "os"
+
+// hookFS wraps the real filesystem and calls before ahead of every
+// operation. A non-nil error from before is returned instead of performing
+// the operation.
+type hookFS struct {
+	osFS
+	before func(op, name string) error
+}
+
+func (h *hookFS) call(op, name string) error {
+	if h.before == nil {
+		return nil
+	}
+	return h.before(op, name)
+}
+
+func (h *hookFS) Stat(name string) (os.FileInfo, error) {
+	if err := h.call("stat", name); err != nil {
+		return nil, err
+	}
+	return h.osFS.Stat(name)
+}
+
+func (h *hookFS) Lstat(name string) (os.FileInfo, error) {
+	if err := h.call("lstat", name); err != nil {
+		return nil, err
+	}
+	return h.osFS.Lstat(name)
+}
+
+func (h *hookFS) ReadDir(name string) ([]os.DirEntry, error) {
+	if err := h.call("readdir", name); err != nil {
+		return nil, err
+	}
+	return h.osFS.ReadDir(name)
+}
+
+func (h *hookFS) Open(name string) (file, error) {
+	if err := h.call("open", name); err != nil {
+		return nil, err
+	}
+	return h.osFS.Open(name)
+}
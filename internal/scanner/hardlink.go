@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"sort"
+
+	"filesystem-logger/internal/models"
+)
+
+// hardLinkTracker groups hard-linked files by device and inode and counts
+// the size of every file once. Inode numbers are only unique per device, so
+// both are part of the key. It is only used from the collector goroutine.
+type hardLinkTracker struct {
+	groups     map[fileKey][]string
+	uniqueSize int64
+}
+
+func newHardLinkTracker() *hardLinkTracker {
+	return &hardLinkTracker{
+		groups: make(map[fileKey][]string),
+	}
+}
+
+func (h *hardLinkTracker) add(file models.FileInfo) {
+	if file.IsDirectory {
+		return
+	}
+	if file.Inode == 0 {
+		h.uniqueSize += file.Size
+		return
+	}
+
+	id := fileKey{dev: file.Device, ino: file.Inode}
+	if _, seen := h.groups[id]; !seen {
+		h.uniqueSize += file.Size
+	}
+	h.groups[id] = append(h.groups[id], file.Path)
+}
+
+// apply stores the groups with more than one path found in the scan, ordered
+// by device and inode
+func (h *hardLinkTracker) apply(result *models.ScanResult) {
+	result.HardLinks = nil
+	for id, paths := range h.groups {
+		if len(paths) > 1 {
+			result.HardLinks = append(result.HardLinks, models.HardLinkGroup{Device: id.dev, Inode: id.ino, Paths: paths})
+		}
+	}
+	sort.Slice(result.HardLinks, func(i, j int) bool {
+		a, b := result.HardLinks[i], result.HardLinks[j]
+		if a.Device != b.Device {
+			return a.Device < b.Device
+		}
+		return a.Inode < b.Inode
+	})
+	result.UniqueSize = h.uniqueSize
+}
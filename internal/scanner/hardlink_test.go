@@ -0,0 +1,77 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestDetectHardLinks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	original := filepath.Join(tempDir, "original.bin")
+	link := filepath.Join(tempDir, "link.bin")
+	other := filepath.Join(tempDir, "other.bin")
+
+	if err := os.WriteFile(original, make([]byte, 1000), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Link(original, link); err != nil {
+		t.Fatalf("Failed to create hard link: %v", err)
+	}
+	if err := os.WriteFile(other, make([]byte, 500), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, DetectHardLinks: true})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(result.HardLinks) != 1 {
+		t.Fatalf("Expected 1 hard link group, got %d: %v", len(result.HardLinks), result.HardLinks)
+	}
+	for _, group := range result.HardLinks {
+		paths := group.Paths
+		sort.Strings(paths)
+		if len(paths) != 2 || paths[0] != link || paths[1] != original {
+			t.Errorf("Expected group [%s %s], got %v", link, original, paths)
+		}
+	}
+
+	if result.UniqueSize != 1500 {
+		t.Errorf("Expected UniqueSize=1500, got %d", result.UniqueSize)
+	}
+	if result.Progress.ScannedSize != 2500 {
+		t.Errorf("Expected ScannedSize=2500, got %d", result.Progress.ScannedSize)
+	}
+}
+
+func TestHardLinkTrackerKeysOnDevice(t *testing.T) {
+	links := newHardLinkTracker()
+	// Dezelfde inode op twee apparaten is niet hetzelfde bestand
+	for _, file := range []models.FileInfo{
+		{Path: "/mnt/a/one", Size: 100, Device: 1, Inode: 42},
+		{Path: "/mnt/a/two", Size: 100, Device: 1, Inode: 42},
+		{Path: "/mnt/b/other", Size: 300, Device: 2, Inode: 42},
+	} {
+		links.add(file)
+	}
+
+	var result models.ScanResult
+	links.apply(&result)
+	expected := []models.HardLinkGroup{{Device: 1, Inode: 42, Paths: []string{"/mnt/a/one", "/mnt/a/two"}}}
+	if !reflect.DeepEqual(result.HardLinks, expected) {
+		t.Errorf("Expected hard links %v, got %v", expected, result.HardLinks)
+	}
+	if result.UniqueSize != 400 {
+		t.Errorf("Expected UniqueSize=400, got %d", result.UniqueSize)
+	}
+}
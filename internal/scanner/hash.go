@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"filesystem-logger/internal/models"
+
+	"lukechampine.com/blake3"
+)
+
+// hashAlgorithmName normalizes a HashAlgorithm setting; empty means SHA-256
+func hashAlgorithmName(algorithm string) string {
+	if algorithm == "" {
+		return models.HashSHA256
+	}
+	return strings.ToLower(algorithm)
+}
+
+// newHasher returns the constructor for the named hash algorithm
+func newHasher(algorithm string) (func() hash.Hash, error) {
+	switch hashAlgorithmName(algorithm) {
+	case models.HashSHA256:
+		return sha256.New, nil
+	case models.HashSHA1:
+		return sha1.New, nil
+	case models.HashMD5:
+		return md5.New, nil
+	case models.HashBLAKE3:
+		return func() hash.Hash { return blake3.New(32, nil) }, nil
+	default:
+		return nil, fmt.Errorf("unknown hash algorithm %q", algorithm)
+	}
+}
+
+// hashContent returns the hex encoded hash of everything read from r
+func hashContent(r io.Reader, newHash func() hash.Hash) (string, error) {
+	h := newHash()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// quickHashSampleSize is how much of the head and of the tail of a file
+// quickHashContent reads
+const quickHashSampleSize = 64 * 1024
+
+// quickHashContent returns the hex encoded hash of the file size followed by
+// the first and last quickHashSampleSize bytes of f. Files of up to twice
+// the sample size are hashed completely. Changes confined to the middle of a
+// larger file do not alter the result, so equal quick hashes only mark
+// candidates that still need a full hash to be confirmed as duplicates.
+func quickHashContent(f io.ReadSeeker, size int64, newHash func() hash.Hash) (string, error) {
+	h := newHash()
+	binary.Write(h, binary.BigEndian, size)
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if size <= 2*quickHashSampleSize {
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	if _, err := io.CopyN(h, f, quickHashSampleSize); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(size-quickHashSampleSize, io.SeekStart); err != nil {
+		return "", err
+	}
+	if _, err := io.CopyN(h, f, quickHashSampleSize); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadHashBlocklist reads a file of SHA-256 hashes. Each line holds one hex
+// hash, optionally followed by whitespace and a file name as written by
+// sha256sum. Blank lines and lines starting with # are ignored.
+func loadHashBlocklist(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hash blocklist: %v", err)
+	}
+	defer f.Close()
+
+	hashes := make(map[string]struct{})
+	lines := bufio.NewScanner(f)
+	for lineNo := 1; lines.Scan(); lineNo++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash := strings.ToLower(strings.Fields(line)[0])
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			// De regel zelf blijft buiten de fout; die kan geheime inhoud bevatten
+			return nil, fmt.Errorf("invalid SHA-256 hash on line %d of %s", lineNo, path)
+		}
+		hashes[hash] = struct{}{}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hash blocklist: %v", err)
+	}
+
+	return hashes, nil
+}
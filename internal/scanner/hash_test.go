@@ -0,0 +1,254 @@
+package scanner
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestHashBlocklist(t *testing.T) {
+	tempDir := t.TempDir()
+
+	malware := []byte("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-TEST-FILE")
+	sum := sha256.Sum256(malware)
+
+	testFiles := map[string][]byte{
+		"dropper.exe": malware,
+		"notes.txt":   []byte("nothing to see here"),
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	blocklist := filepath.Join(t.TempDir(), "hashes.txt")
+	content := "# known-bad samples\n\n" + hex.EncodeToString(sum[:]) + "  dropper.exe\n"
+	if err := os.WriteFile(blocklist, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create blocklist: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, HashBlocklistPath: blocklist})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		switch file.Name {
+		case "dropper.exe":
+			if !file.IsBlocked || file.BlockReason != "matches known-bad hash" {
+				t.Errorf("Expected dropper.exe to be blocked by hash, got blocked=%v reason=%q",
+					file.IsBlocked, file.BlockReason)
+			}
+			if file.Hash != hex.EncodeToString(sum[:]) {
+				t.Errorf("Expected hash %x, got %s", sum, file.Hash)
+			}
+		case "notes.txt":
+			if file.IsBlocked {
+				t.Errorf("Expected notes.txt not to be blocked: %s", file.BlockReason)
+			}
+		}
+	}
+}
+
+func TestLoadHashBlocklistErrors(t *testing.T) {
+	tempDir := t.TempDir()
+
+	invalid := filepath.Join(tempDir, "invalid.txt")
+	if err := os.WriteFile(invalid, []byte("not-a-hash\n"), 0644); err != nil {
+		t.Fatalf("Failed to create blocklist: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "Invalid line", path: invalid},
+		{name: "Missing file", path: filepath.Join(tempDir, "missing.txt")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{HashBlocklistPath: tt.path})
+			_, err := scanner.Scan(tempDir)
+			if err == nil {
+				t.Fatal("Expected Scan to report the blocklist error")
+			}
+			if strings.Contains(err.Error(), "not-a-hash") {
+				t.Errorf("Expected the error not to quote the file contents, got %v", err)
+			}
+		})
+	}
+}
+
+func TestQuickHash(t *testing.T) {
+	tempDir := t.TempDir()
+
+	content := make([]byte, 4*quickHashSampleSize)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	changed := append([]byte(nil), content...)
+	changed[len(changed)/2] ^= 0xFF
+
+	testFiles := map[string][]byte{
+		"original.bin": content,
+		"changed.bin":  changed,
+		"small.bin":    []byte("small file"),
+	}
+	for name, data := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), data, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, QuickHash: true, ComputeHash: true})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	files := make(map[string]models.FileInfo)
+	for _, file := range result.Files {
+		files[file.Name] = file
+	}
+
+	original, changedFile := files["original.bin"], files["changed.bin"]
+	if original.QuickHash == "" {
+		t.Fatal("Expected a quick hash to be computed")
+	}
+	if original.QuickHash != changedFile.QuickHash {
+		t.Error("Expected files identical in their sampled regions to share a quick hash")
+	}
+	if original.Hash == changedFile.Hash {
+		t.Error("Expected the full hash to tell the files apart")
+	}
+	if files["small.bin"].QuickHash == "" || files["small.bin"].QuickHash == original.QuickHash {
+		t.Errorf("Expected a distinct quick hash for small.bin, got %q", files["small.bin"].QuickHash)
+	}
+}
+
+func TestHashAlgorithm(t *testing.T) {
+	tempDir := t.TempDir()
+
+	content := []byte("hash me")
+	if err := os.WriteFile(filepath.Join(tempDir, "file.txt"), content, 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	sha1Sum := sha1.Sum(content)
+	md5Sum := md5.Sum(content)
+	sha256Sum := sha256.Sum256(content)
+
+	tests := []struct {
+		algorithm string
+		name      string
+		hexLength int
+		expected  string
+	}{
+		{algorithm: "", name: models.HashSHA256, hexLength: 64, expected: hex.EncodeToString(sha256Sum[:])},
+		{algorithm: models.HashSHA256, name: models.HashSHA256, hexLength: 64, expected: hex.EncodeToString(sha256Sum[:])},
+		{algorithm: models.HashSHA1, name: models.HashSHA1, hexLength: 40, expected: hex.EncodeToString(sha1Sum[:])},
+		{algorithm: models.HashMD5, name: models.HashMD5, hexLength: 32, expected: hex.EncodeToString(md5Sum[:])},
+		{algorithm: "BLAKE3", name: models.HashBLAKE3, hexLength: 64},
+	}
+
+	blake3Hashes := make(map[string]bool)
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.algorithm, func(t *testing.T) {
+			scanner := New(models.ScanConfig{MaxFileSizeMB: 10, ComputeHash: true, QuickHash: true, HashAlgorithm: tt.algorithm})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			if result.HashAlgorithm != tt.name {
+				t.Errorf("Expected hash algorithm %s in the result, got %q", tt.name, result.HashAlgorithm)
+			}
+			for _, file := range result.Files {
+				if file.IsDirectory {
+					continue
+				}
+				if len(file.Hash) != tt.hexLength || len(file.QuickHash) != tt.hexLength {
+					t.Errorf("Expected %d hex characters, got hash %q and quick hash %q", tt.hexLength, file.Hash, file.QuickHash)
+				}
+				if _, err := hex.DecodeString(file.Hash); err != nil {
+					t.Errorf("Expected a hex encoded hash, got %q", file.Hash)
+				}
+				if tt.expected != "" && file.Hash != tt.expected {
+					t.Errorf("Expected hash %s, got %s", tt.expected, file.Hash)
+				}
+				if tt.name == models.HashBLAKE3 {
+					blake3Hashes[file.Hash] = true
+				}
+			}
+		})
+	}
+	if blake3Hashes[hex.EncodeToString(sha256Sum[:])] {
+		t.Error("Expected the BLAKE3 hash to differ from SHA-256")
+	}
+
+	t.Run("BLAKE3 test vectors", func(t *testing.T) {
+		// Uitkomsten uit de officiële BLAKE3 testvectoren
+		vectors := map[string]struct {
+			content  string
+			expected string
+		}{
+			"empty.bin": {"", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+			"abc.txt":   {"abc", "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"},
+		}
+		vectorDir := t.TempDir()
+		for name, vector := range vectors {
+			if err := os.WriteFile(filepath.Join(vectorDir, name), []byte(vector.content), 0644); err != nil {
+				t.Fatalf("Failed to create test file %s: %v", name, err)
+			}
+		}
+
+		result, err := New(models.ScanConfig{MaxFileSizeMB: 10, ComputeHash: true, HashAlgorithm: models.HashBLAKE3}).Scan(vectorDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		hashes := make(map[string]string)
+		for _, file := range result.Files {
+			hashes[file.Name] = file.Hash
+		}
+		for name, vector := range vectors {
+			if hashes[name] != vector.expected {
+				t.Errorf("Expected BLAKE3 %s for %q, got %q", vector.expected, vector.content, hashes[name])
+			}
+		}
+	})
+
+	t.Run("Without hashing", func(t *testing.T) {
+		result, err := New(models.ScanConfig{MaxFileSizeMB: 10, HashAlgorithm: models.HashMD5}).Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if result.HashAlgorithm != "" {
+			t.Errorf("Expected no hash algorithm without hashing, got %q", result.HashAlgorithm)
+		}
+	})
+
+	t.Run("Unknown algorithm", func(t *testing.T) {
+		if _, err := New(models.ScanConfig{ComputeHash: true, HashAlgorithm: "crc32"}).Scan(tempDir); err == nil {
+			t.Error("Expected an error for an unknown hash algorithm")
+		}
+	})
+
+	t.Run("Blocklist needs SHA-256", func(t *testing.T) {
+		blocklist := filepath.Join(t.TempDir(), "hashes.txt")
+		if err := os.WriteFile(blocklist, []byte(hex.EncodeToString(sha256Sum[:])+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to create blocklist: %v", err)
+		}
+		if _, err := New(models.ScanConfig{HashBlocklistPath: blocklist, HashAlgorithm: models.HashBLAKE3}).Scan(tempDir); err == nil {
+			t.Error("Expected an error combining the hash blocklist with BLAKE3")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+// incrementalExport appends blocked files to IncrementalExportPath as NDJSON
+// while the scan runs. Every line is written straight to the file, so a
+// crash leaves the files found so far; a summary line is appended when the
+// scan ends. Only the result collector writes to it.
+type incrementalExport struct {
+	file    *os.File
+	encoder *json.Encoder
+	err     error
+
+	blockedCount int64
+	blockedSize  int64
+}
+
+// incrementalSummary is the footer line of the incremental export. Complete
+// is false when the scan was cancelled before it saw the whole tree.
+type incrementalSummary struct {
+	Summary struct {
+		TotalFiles        int64         `json:"totalFiles"`
+		BlockedCount      int64         `json:"blockedCount"`
+		BlockedSize       int64         `json:"blockedSize"`
+		ScanDuration      time.Duration `json:"scanDuration"`
+		ScanDurationHuman string        `json:"scanDurationHuman"`
+		Complete          bool          `json:"complete"`
+	} `json:"summary"`
+}
+
+func openIncrementalExport(path string) (*incrementalExport, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create incremental export: %v", err)
+	}
+	return &incrementalExport{file: f, encoder: json.NewEncoder(f)}, nil
+}
+
+// add appends file when it is blocked. Only the first write error is kept.
+func (e *incrementalExport) add(file models.FileInfo) {
+	if e == nil || !file.IsBlocked {
+		return
+	}
+	e.blockedCount++
+	e.blockedSize += file.Size
+	if err := e.encoder.Encode(file); err != nil && e.err == nil {
+		e.err = err
+	}
+}
+
+// finish appends the summary line and closes the export
+func (e *incrementalExport) finish(progress models.ScanProgress, duration time.Duration, complete bool) error {
+	if e == nil {
+		return nil
+	}
+	var summary incrementalSummary
+	summary.Summary.TotalFiles = progress.TotalFiles
+	summary.Summary.BlockedCount = e.blockedCount
+	summary.Summary.BlockedSize = e.blockedSize
+	summary.Summary.ScanDuration = duration
+	summary.Summary.ScanDurationHuman = duration.String()
+	summary.Summary.Complete = complete
+	if err := e.encoder.Encode(summary); err != nil && e.err == nil {
+		e.err = err
+	}
+	if err := e.file.Close(); err != nil && e.err == nil {
+		e.err = err
+	}
+	if e.err != nil {
+		return fmt.Errorf("failed to write incremental export: %v", e.err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+// readIncrementalExport decodes the lines of an incremental export into the
+// blocked files and, when present, the summary footer
+func readIncrementalExport(t *testing.T, path string) ([]models.FileInfo, *incrementalSummary) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read incremental export: %v", err)
+	}
+
+	var files []models.FileInfo
+	var summary *incrementalSummary
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if bytes.HasPrefix(line, []byte(`{"summary"`)) {
+			summary = &incrementalSummary{}
+			if err := json.Unmarshal(line, summary); err != nil {
+				t.Fatalf("Failed to decode summary %s: %v", line, err)
+			}
+			continue
+		}
+		var file models.FileInfo
+		if err := json.Unmarshal(line, &file); err != nil {
+			t.Fatalf("Failed to decode %s: %v", line, err)
+		}
+		files = append(files, file)
+	}
+	return files, summary
+}
+
+func TestIncrementalExportInterrupted(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	exportPath := filepath.Join(tempDir, "blocked.ndjson")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"a.exe", "b.exe", "notes.txt", "z.exe"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	// De scan blijft hangen op z.exe totdat de test hem loslaat
+	stuck := filepath.Join(root, "z.exe")
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:         10,
+		BlockedPatterns:       []string{"*.exe"},
+		TraversalMode:         models.TraversalSequential,
+		IncrementalExportPath: exportPath,
+	})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "stat" && name == stuck {
+			close(entered)
+			<-release
+		}
+		return nil
+	}}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := scanner.Scan(root)
+		done <- err
+	}()
+
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scan never reached the blocking file")
+	}
+
+	// The collector writes asynchronously; wait for both blocked files
+	var files []models.FileInfo
+	var summary *incrementalSummary
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if files, summary = readIncrementalExport(t, exportPath); len(files) >= 2 {
+			break
+		}
+	}
+	if len(files) != 2 || files[0].Name != "a.exe" || files[1].Name != "b.exe" {
+		t.Fatalf("Expected the partial export to hold a.exe and b.exe, got %v", files)
+	}
+	if summary != nil {
+		t.Error("Expected no summary while the scan is running")
+	}
+
+	scanner.Cancel()
+	close(release)
+	if err := <-done; !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Expected ErrCancelled, got %v", err)
+	}
+
+	files, summary = readIncrementalExport(t, exportPath)
+	if summary == nil {
+		t.Fatal("Expected a summary line after the scan ended")
+	}
+	if summary.Summary.Complete {
+		t.Error("Expected the summary of a cancelled scan to be incomplete")
+	}
+	if summary.Summary.BlockedCount != int64(len(files)) {
+		t.Errorf("Expected blockedCount %d, got %d", len(files), summary.Summary.BlockedCount)
+	}
+}
+
+func TestIncrementalExportComplete(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	exportPath := filepath.Join(tempDir, "blocked.ndjson")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"setup.exe", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:         10,
+		BlockedPatterns:       []string{"*.exe"},
+		IncrementalExportPath: exportPath,
+	})
+	if _, err := scanner.Scan(root); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	files, summary := readIncrementalExport(t, exportPath)
+	if len(files) != 1 || files[0].Name != "setup.exe" {
+		t.Errorf("Expected only setup.exe, got %v", files)
+	}
+	if summary == nil || !summary.Summary.Complete || summary.Summary.BlockedCount != 1 {
+		t.Errorf("Expected a complete summary with 1 blocked file, got %+v", summary)
+	}
+}
+
+func TestIncrementalExportFailedScan(t *testing.T) {
+	for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+		t.Run(mode, func(t *testing.T) {
+			tempDir := t.TempDir()
+			first := filepath.Join(tempDir, "roots", "first")
+			second := filepath.Join(tempDir, "roots", "second")
+			exportPath := filepath.Join(tempDir, "blocked.ndjson")
+			checkpointPath := filepath.Join(tempDir, "scan.checkpoint")
+			for _, dir := range []string{first, second} {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "setup.exe"), []byte("test"), 0644); err != nil {
+					t.Fatalf("Failed to create test file: %v", err)
+				}
+			}
+
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:         10,
+				ScanRecursively:       true,
+				TraversalMode:         mode,
+				BlockedPatterns:       []string{"*.exe"},
+				IncrementalExportPath: exportPath,
+				CheckpointPath:        checkpointPath,
+			})
+			// The second root disappears after ScanGlob checked it
+			var stats atomic.Int32
+			scanner.fs = &hookFS{before: func(op, name string) error {
+				if op == "stat" && name == second && stats.Add(1) > 1 {
+					return os.ErrNotExist
+				}
+				return nil
+			}}
+
+			result, err := scanner.ScanGlob(filepath.Join(tempDir, "roots", "*"))
+			if !errors.Is(err, ErrRootNotFound) {
+				t.Fatalf("Expected ErrRootNotFound, got %v", err)
+			}
+			if result != nil {
+				t.Errorf("Expected no result for a failed scan, got %+v", result)
+			}
+
+			_, summary := readIncrementalExport(t, exportPath)
+			if summary == nil {
+				t.Fatal("Expected a summary line after the scan failed")
+			}
+			if summary.Summary.Complete {
+				t.Error("Expected the summary of a failed scan to be incomplete")
+			}
+			if _, err := os.Stat(checkpointPath); err != nil {
+				t.Errorf("Expected the checkpoint to be kept for a resume: %v", err)
+			}
+		})
+	}
+}
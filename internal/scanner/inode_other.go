@@ -0,0 +1,15 @@
+//go:build !unix
+
+package scanner
+
+import "os"
+
+// hardLinkID is not supported on this platform
+func hardLinkID(info os.FileInfo) (id fileKey, linked bool) {
+	return fileKey{}, false
+}
+
+// fileID is not supported on this platform
+func fileID(info os.FileInfo) (id fileKey, ok bool) {
+	return fileKey{}, false
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"syscall"
+)
+
+// hardLinkID returns the device and inode of a file and whether it has more
+// than one link
+func hardLinkID(info os.FileInfo) (id fileKey, linked bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, false
+	}
+	return fileKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, uint64(stat.Nlink) > 1
+}
+
+// fileID returns the device and inode identifying the file behind info
+func fileID(info os.FileInfo) (id fileKey, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, false
+	}
+	return fileKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, true
+}
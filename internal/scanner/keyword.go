@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"bytes"
+	"io"
+
+	"filesystem-logger/internal/models"
+)
+
+// keywordChunk is the number of bytes read at a time while searching a file
+// for keywords
+var keywordChunk = 64 * 1024
+
+// searchesKeywords reports whether file is searched for Keywords: it is
+// previewed as text and within the size limit
+func (s *Scanner) searchesKeywords(file *models.FileInfo) bool {
+	return len(s.config.Keywords) > 0 && s.isText(file) && s.isFileSizeAllowed(file.Size)
+}
+
+// findKeywords searches the content of r for keywords, ignoring case, and
+// returns the ones found in the order they are listed. The end of every read
+// is kept for the next one, so matches spanning two reads are found too.
+func findKeywords(r io.Reader, keywords []string) ([]string, error) {
+	lower := make([][]byte, len(keywords))
+	longest := 0
+	for i, keyword := range keywords {
+		lower[i] = bytes.ToLower([]byte(keyword))
+		longest = max(longest, len(lower[i]))
+	}
+	if longest == 0 {
+		return nil, nil
+	}
+
+	found := make([]bool, len(keywords))
+	remaining := 0
+	for _, keyword := range lower {
+		if len(keyword) > 0 {
+			remaining++
+		}
+	}
+
+	window := make([]byte, 0, longest-1+keywordChunk)
+	chunk := make([]byte, keywordChunk)
+	for remaining > 0 {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			window = append(window, chunk[:n]...)
+			text := bytes.ToLower(window)
+			for i, keyword := range lower {
+				if !found[i] && len(keyword) > 0 && bytes.Contains(text, keyword) {
+					found[i] = true
+					remaining--
+				}
+			}
+			// Houd genoeg over voor een treffer die over de grens loopt
+			if keep := longest - 1; len(window) > keep {
+				window = append(window[:0], window[len(window)-keep:]...)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var matched []string
+	for i, keyword := range keywords {
+		if found[i] {
+			matched = append(matched, keyword)
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestFindKeywords(t *testing.T) {
+	// Kleine leesblokken zodat treffers over de grens heen lopen
+	defer func(chunk int) { keywordChunk = chunk }(keywordChunk)
+	keywordChunk = 4
+
+	tests := []struct {
+		name     string
+		content  string
+		keywords []string
+		expected []string
+	}{
+		{name: "Across reads", content: "Contains a PassWord and a TOKEN", keywords: []string{"token", "password"}, expected: []string{"token", "password"}},
+		{name: "Missing keyword", content: "nothing to see", keywords: []string{"secret"}, expected: nil},
+		{name: "Empty keyword", content: "anything", keywords: []string{"", "thing"}, expected: []string{"thing"}},
+		{name: "No keywords", content: "anything", keywords: nil, expected: nil},
+		{name: "Empty content", content: "", keywords: []string{"secret"}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := findKeywords(strings.NewReader(tt.content), tt.keywords)
+			if err != nil {
+				t.Fatalf("findKeywords failed: %v", err)
+			}
+			if !reflect.DeepEqual(matched, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, matched)
+			}
+		})
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Een stuurteken laat http.DetectContentType het bestand als binair zien
+	files := map[string]string{
+		"app.conf":  "\x01# service config\npassword = hunter2\n",
+		"notes.txt": "remember the Password",
+		"clean.txt": "nothing here",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		textExtensions []string
+		expected       map[string][]string
+	}{
+		{
+			name:     "Detected as binary",
+			expected: map[string][]string{"notes.txt": {"password"}},
+		},
+		{
+			name:           "Listed as text",
+			textExtensions: []string{".conf"},
+			expected:       map[string][]string{"app.conf": {"password"}, "notes.txt": {"password"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:  10,
+				Keywords:       []string{"password"},
+				TextExtensions: tt.textExtensions,
+			})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			for _, file := range result.Files {
+				if file.IsDirectory {
+					continue
+				}
+				if !reflect.DeepEqual(file.MatchedKeywords, tt.expected[file.Name]) {
+					t.Errorf("Expected %s to match %v, got %v", file.Name, tt.expected[file.Name], file.MatchedKeywords)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package scanner
+
+import "os"
+
+// fileKey identifies a file by device and inode
+type fileKey struct {
+	dev, ino uint64
+}
+
+// isDuplicateLink reports whether the file behind info was already counted
+// and this encounter, or the earlier one, went through a symlink. Hard links
+// found without a symlink are still counted separately; DetectHardLinks
+// covers those.
+func (s *Scanner) isDuplicateLink(isSymlink bool, info os.FileInfo) bool {
+	id, ok := fileID(info)
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	viaLink, seen := s.linkTargets[id]
+	if !seen {
+		if s.linkTargets == nil {
+			s.linkTargets = make(map[fileKey]bool)
+		}
+		s.linkTargets[id] = isSymlink
+		return false
+	}
+	return isSymlink || viaLink
+}
@@ -0,0 +1,72 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestDuplicateLinkTargets(t *testing.T) {
+	tempDir := t.TempDir()
+
+	target := filepath.Join(tempDir, "target.bin")
+	if err := os.WriteFile(target, make([]byte, 1000), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "other.bin"), make([]byte, 500), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	for _, name := range []string{"link1", "link2"} {
+		if err := os.Symlink(target, filepath.Join(tempDir, name)); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+	}
+
+	for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+		t.Run(mode, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:      10,
+				FollowSymlinkFiles: true,
+				TraversalMode:      mode,
+			})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			if result.Progress.ScannedSize != 1500 {
+				t.Errorf("Expected scanned size 1500, got %d", result.Progress.ScannedSize)
+			}
+
+			duplicates := 0
+			for _, file := range result.Files {
+				if file.IsDuplicateLink {
+					duplicates++
+					if file.Name == "other.bin" {
+						t.Errorf("Expected other.bin not to be a duplicate")
+					}
+				}
+			}
+			if duplicates != 2 {
+				t.Errorf("Expected 2 duplicate links, got %d", duplicates)
+			}
+		})
+	}
+
+	t.Run("Not following", func(t *testing.T) {
+		scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		for _, file := range result.Files {
+			if file.IsDuplicateLink {
+				t.Errorf("Expected no duplicate links without FollowSymlinkFiles, got %s", file.Path)
+			}
+		}
+	})
+}
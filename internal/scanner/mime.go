@@ -0,0 +1,201 @@
+package scanner
+
+import (
+	"bytes"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"filesystem-logger/internal/models"
+)
+
+// defaultFileTypeNames maps (compound) extensions to friendly type names.
+// Entries from ScanConfig.FileTypeNames take precedence.
+var defaultFileTypeNames = map[string]string{
+	".tar.gz":  "gzip-tarball",
+	".tgz":     "gzip-tarball",
+	".tar.bz2": "bzip2-tarball",
+	".tbz2":    "bzip2-tarball",
+	".tar.xz":  "xz-tarball",
+	".txz":     "xz-tarball",
+	".tar.zst": "zstd-tarball",
+}
+
+// expectedMimeTypes maps extensions to the MIME type their content should
+// sniff as. Only formats with a reliable signature are listed.
+var expectedMimeTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".bmp":  "image/bmp",
+	".webp": "image/webp",
+	".pdf":  "application/pdf",
+	".zip":  "application/zip",
+	".gz":   "application/x-gzip",
+	".rar":  "application/x-rar-compressed",
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wave",
+	".exe":  "application/x-msdownload",
+	".dll":  "application/x-msdownload",
+}
+
+// extensionMimeTypes maps extensions to MIME types for TrustExtensionFor.
+// They cover common large binary formats that the mime package may not
+// know without a system MIME table.
+var extensionMimeTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".avi":  "video/x-msvideo",
+	".mov":  "video/quicktime",
+	".wmv":  "video/x-ms-wmv",
+	".mp3":  "audio/mpeg",
+	".flac": "audio/flac",
+	".ogg":  "audio/ogg",
+	".wav":  "audio/wave",
+	".iso":  "application/x-iso9660-image",
+	".zip":  "application/zip",
+	".7z":   "application/x-7z-compressed",
+	".rar":  "application/x-rar-compressed",
+	".gz":   "application/x-gzip",
+	".tar":  "application/x-tar",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".pdf":  "application/pdf",
+}
+
+// mimeForExtension returns the MIME type for a lowercase extension, falling
+// back to the mime package and then to application/octet-stream
+func mimeForExtension(ext string) string {
+	if mimeType, ok := extensionMimeTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		return mimeType
+	}
+	return "application/octet-stream"
+}
+
+// executableSignatures identifies executables, which http.DetectContentType
+// reports as application/octet-stream
+var executableSignatures = []struct {
+	magic    []byte
+	mimeType string
+}{
+	{[]byte("MZ"), "application/x-msdownload"},
+	{[]byte("\x7fELF"), "application/x-elf"},
+}
+
+// sniffExecutable returns the MIME type of an executable header, or an empty
+// string when data does not start with a known executable signature
+func sniffExecutable(data []byte) string {
+	for _, sig := range executableSignatures {
+		if bytes.HasPrefix(data, sig.magic) {
+			return sig.mimeType
+		}
+	}
+	return ""
+}
+
+// extensionMismatch reports whether the content of a file contradicts its
+// extension. Extensions without a known signature never mismatch, so
+// libraries such as .so.1 or extensionless binaries are not flagged.
+func extensionMismatch(ext, detectedMime string, data []byte) bool {
+	expected, ok := expectedMimeTypes[strings.ToLower(ext)]
+	if !ok {
+		return false
+	}
+
+	actual := detectedMime
+	if exe := sniffExecutable(data); exe != "" {
+		actual = exe
+	}
+	if mediaType, _, err := mime.ParseMediaType(actual); err == nil {
+		actual = mediaType
+	}
+
+	return actual != expected
+}
+
+// fileTypeFor derives a friendly file type, preferring a two-part extension
+// such as .tar.gz over the last extension. Extensionless files fall back to
+// the MIME category.
+func (s *Scanner) fileTypeFor(name, mimeType string) string {
+	name = strings.ToLower(name)
+	ext := filepath.Ext(name)
+	if ext == "" {
+		return strings.Split(mimeType, "/")[0]
+	}
+
+	if inner := filepath.Ext(strings.TrimSuffix(name, ext)); inner != "" {
+		if friendly, ok := s.lookupFileType(inner + ext); ok {
+			return friendly
+		}
+	}
+	if friendly, ok := s.lookupFileType(ext); ok {
+		return friendly
+	}
+
+	return strings.TrimPrefix(ext, ".")
+}
+
+// contentTypeNames names detected MIME types whose subtype makes a poor file
+// type for PreferContentType
+var contentTypeNames = map[string]string{
+	"image/jpeg":                   "jpg",
+	"audio/mpeg":                   "mp3",
+	"audio/wave":                   "wav",
+	"application/x-gzip":           "gz",
+	"application/x-rar-compressed": "rar",
+	"application/x-msdownload":     "exe",
+	"application/x-elf":            "elf",
+}
+
+// contentFileType returns the file type for a sniffed file. With
+// PreferContentType set, a detected type that differs from what the
+// extension promises wins over the extension. Generic results such as
+// text/plain and application/octet-stream never override the extension.
+func (s *Scanner) contentFileType(file *models.FileInfo, head []byte) string {
+	extType := s.fileTypeFor(file.Name, file.MimeType)
+	if !s.config.PreferContentType {
+		return extType
+	}
+
+	detected := file.MimeType
+	if exe := sniffExecutable(head); exe != "" {
+		detected = exe
+	}
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil || mediaType == "application/octet-stream" || mediaType == "text/plain" {
+		return extType
+	}
+	if expected, _, err := mime.ParseMediaType(mimeForExtension(strings.ToLower(file.Extension))); err == nil && expected == mediaType {
+		return extType
+	}
+
+	if name, ok := contentTypeNames[mediaType]; ok {
+		return name
+	}
+	_, subtype, _ := strings.Cut(mediaType, "/")
+	return strings.TrimPrefix(subtype, "x-")
+}
+
+func (s *Scanner) lookupFileType(ext string) (string, bool) {
+	if friendly, ok := s.fileTypeNames[ext]; ok {
+		return friendly, true
+	}
+	friendly, ok := defaultFileTypeNames[ext]
+	return friendly, ok
+}
+
+// categoryFor buckets a file by its extension overrides from
+// ScanConfig.Categories, falling back to models.CategoryForMime
+func (s *Scanner) categoryFor(ext, mimeType string) string {
+	if category, ok := s.categories[strings.ToLower(ext)]; ok {
+		return category
+	}
+	return models.CategoryForMime(mimeType, ext)
+}
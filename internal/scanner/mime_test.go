@@ -0,0 +1,223 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestExtensionMismatch(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string][]byte{
+		"holiday.jpg": append([]byte("MZ\x90\x00\x03\x00\x00\x00"), make([]byte, 64)...), // PE header
+		"real.jpg":    {0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'},
+		"notes.txt":   []byte("plain text"),
+		// Binaries behind extensions without a known signature
+		"libfoo.so.1":  append([]byte("\x7fELF\x02\x01\x01"), make([]byte, 64)...),
+		"tool":         append([]byte("\x7fELF\x02\x01\x01"), make([]byte, 64)...),
+		"firmware.bin": append([]byte("MZ\x90\x00\x03\x00\x00\x00"), make([]byte, 64)...),
+		"setup.exe":    append([]byte("\x7fELF\x02\x01\x01"), make([]byte, 64)...), // ELF, not PE
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:          10,
+		FlagExtensionMismatch:  true,
+		BlockExtensionMismatch: true,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		if file.IsDirectory {
+			continue
+		}
+		expectMismatch := file.Name == "holiday.jpg" || file.Name == "setup.exe"
+		if file.ExtensionMismatch != expectMismatch {
+			t.Errorf("Expected ExtensionMismatch=%v for %s (mime %s)", expectMismatch, file.Name, file.MimeType)
+		}
+		if file.IsBlocked != expectMismatch {
+			t.Errorf("Expected IsBlocked=%v for %s, reason %q", expectMismatch, file.Name, file.BlockReason)
+		}
+	}
+}
+
+func TestFileTypeNames(t *testing.T) {
+	scanner := New(models.ScanConfig{
+		FileTypeNames: map[string]string{".LOG": "logfile"},
+	})
+
+	tests := []struct {
+		name     string
+		mimeType string
+		expected string
+	}{
+		{name: "backup.tar.gz", mimeType: "application/x-gzip", expected: "gzip-tarball"},
+		{name: "backup.TAR.BZ2", mimeType: "application/octet-stream", expected: "bzip2-tarball"},
+		{name: "archive.gz", mimeType: "application/x-gzip", expected: "gz"},
+		{name: "data.xyz", mimeType: "application/octet-stream", expected: "xyz"},
+		{name: "server.log", mimeType: "text/plain; charset=utf-8", expected: "logfile"},
+		{name: "release.v1.2.txt", mimeType: "text/plain; charset=utf-8", expected: "txt"},
+		{name: "README", mimeType: "text/plain; charset=utf-8", expected: "text"},
+		{name: ".bashrc", mimeType: "text/plain; charset=utf-8", expected: "bashrc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanner.fileTypeFor(tt.name, tt.mimeType); got != tt.expected {
+				t.Errorf("Expected file type %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCategoryStats(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string][]byte{
+		"photo.jpg":  {0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'},
+		"report.pdf": []byte("%PDF-1.4\n"),
+		"blob.bin":   {0x00, 0x01, 0x02, 0x03},
+		"server.log": []byte("started\n"),
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB: 10,
+		Categories:    map[string]string{".LOG": "logs"},
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"photo.jpg":  models.CategoryImage,
+		"report.pdf": models.CategoryDocument,
+		"blob.bin":   models.CategoryOther,
+		"server.log": "logs",
+	}
+	for _, file := range result.Files {
+		if file.IsDirectory {
+			if file.Category != "" {
+				t.Errorf("Expected no category for directory %s, got %s", file.Name, file.Category)
+			}
+			continue
+		}
+		if file.Category != expected[file.Name] {
+			t.Errorf("Expected category %s for %s, got %s", expected[file.Name], file.Name, file.Category)
+		}
+	}
+
+	for _, category := range expected {
+		if result.CategoryStats[category] != 1 {
+			t.Errorf("Expected 1 file in category %s, got %d", category, result.CategoryStats[category])
+		}
+	}
+}
+
+func TestTrustExtensionFor(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// The content is plain text, so sniffing would never report video/mp4
+	testFiles := map[string]string{
+		"movie.mp4": "not really a movie",
+		"notes.txt": "plain text",
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	for _, computeHash := range []bool{false, true} {
+		opened := make(map[string]bool)
+		var mu sync.Mutex
+		scanner := New(models.ScanConfig{MaxFileSizeMB: 10, TrustExtensionFor: []string{"mp4"}, ComputeHash: computeHash})
+		scanner.fs = &hookFS{before: func(op, name string) error {
+			if op == "open" {
+				mu.Lock()
+				opened[filepath.Base(name)] = true
+				mu.Unlock()
+			}
+			return nil
+		}}
+
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+
+		files := make(map[string]models.FileInfo)
+		for _, file := range result.Files {
+			files[file.Name] = file
+		}
+
+		movie := files["movie.mp4"]
+		if movie.MimeType != "video/mp4" || movie.Category != "video" {
+			t.Errorf("Expected movie.mp4 to be video/mp4 in the video category, got %q and %q", movie.MimeType, movie.Category)
+		}
+		if opened["movie.mp4"] != computeHash {
+			t.Errorf("Expected movie.mp4 opened=%v with ComputeHash=%v", computeHash, computeHash)
+		}
+		if computeHash && movie.Hash == "" {
+			t.Error("Expected trusted files to be hashed when ComputeHash is set")
+		}
+		if !opened["notes.txt"] || !strings.HasPrefix(files["notes.txt"].MimeType, "text/plain") {
+			t.Errorf("Expected notes.txt to be sniffed as text/plain, got %q", files["notes.txt"].MimeType)
+		}
+	}
+}
+
+func TestPreferContentType(t *testing.T) {
+	tempDir := t.TempDir()
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	testFiles := map[string][]byte{
+		"picture.txt": png,
+		"photo.png":   png,
+		"notes.go":    []byte("package main\n"),
+		"tool.dat":    []byte("MZ\x90\x00rest of an executable"),
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		prefer   bool
+		expected map[string]string
+	}{
+		{prefer: false, expected: map[string]string{"picture.txt": "txt", "photo.png": "png", "notes.go": "go", "tool.dat": "dat"}},
+		{prefer: true, expected: map[string]string{"picture.txt": "png", "photo.png": "png", "notes.go": "go", "tool.dat": "exe"}},
+	}
+
+	for _, tt := range tests {
+		scanner := New(models.ScanConfig{MaxFileSizeMB: 10, PreferContentType: tt.prefer})
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		for _, file := range result.Files {
+			if expected, ok := tt.expected[file.Name]; ok && file.FileType != expected {
+				t.Errorf("Expected %s to have file type %q with PreferContentType=%v, got %q", file.Name, expected, tt.prefer, file.FileType)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// escapeInvalidUTF8 replaces every byte that is not part of a valid UTF-8
+// sequence with a \xNN escape, leaving valid characters untouched. Unlike
+// encoding/json, which substitutes U+FFFD, the original bytes stay
+// recoverable.
+func escapeInvalidUTF8(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			fmt.Fprintf(&b, `\x%02x`, s[i])
+		} else {
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// isSuspiciousName reports whether name contains control characters, such as
+// newlines, or path separators. Both can break tools that process file lists
+// and are rarely used legitimately.
+func isSuspiciousName(name string) bool {
+	if strings.ContainsAny(name, `/\`) {
+		return true
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return true
+		}
+	}
+	return false
+}
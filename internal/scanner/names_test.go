@@ -0,0 +1,110 @@
+//go:build unix
+
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestInvalidUTF8Name(t *testing.T) {
+	tempDir := t.TempDir()
+
+	badName := "bad\xff\xfename.txt"
+	if err := os.WriteFile(filepath.Join(tempDir, badName), []byte("content"), 0644); err != nil {
+		t.Skipf("Filesystem does not accept invalid UTF-8 names: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "good.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:       10,
+		BlockedPatterns:     []string{"bad*"},
+		ExportBlockedToJSON: true,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(result.Progress.Errors) > 0 {
+		t.Fatalf("Expected no errors, got %v", result.Progress.Errors)
+	}
+
+	for _, file := range result.Files {
+		switch file.Name {
+		case badName:
+			if !file.InvalidName {
+				t.Error("Expected invalid UTF-8 name to be flagged")
+			}
+			if !strings.HasSuffix(file.EscapedPath, `bad\xff\xfename.txt`) {
+				t.Errorf("Expected escaped path, got %q", file.EscapedPath)
+			}
+		case "good.txt":
+			if file.InvalidName || file.EscapedPath != "" {
+				t.Error("Expected valid name not to be flagged")
+			}
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "blocked_files.json"))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	var exported struct {
+		BlockedFiles []models.FileInfo `json:"blockedFiles"`
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Export is not valid JSON: %v", err)
+	}
+	if len(exported.BlockedFiles) != 1 || !strings.HasSuffix(exported.BlockedFiles[0].EscapedPath, `bad\xff\xfename.txt`) {
+		t.Errorf("Expected export to preserve the escaped path, got %+v", exported.BlockedFiles)
+	}
+}
+
+func TestSuspiciousName(t *testing.T) {
+	tempDir := t.TempDir()
+
+	names := []string{"line\nbreak.txt", "bell\a.txt", `back\slash.txt`, "normal.txt"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("content"), 0644); err != nil {
+			t.Skipf("Filesystem does not accept name %q: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		block bool
+	}{
+		{name: "Flag only", block: false},
+		{name: "Flag and block", block: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{MaxFileSizeMB: 10, BlockSuspiciousNames: tt.block})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			for _, file := range result.Files {
+				if file.IsDirectory {
+					continue
+				}
+				suspicious := file.Name != "normal.txt"
+				if file.SuspiciousName != suspicious {
+					t.Errorf("%q: expected SuspiciousName=%v, got %v", file.Name, suspicious, file.SuspiciousName)
+				}
+				if blocked := tt.block && suspicious; file.IsBlocked != blocked {
+					t.Errorf("%q: expected blocked=%v, got %v", file.Name, blocked, file.IsBlocked)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	// openRetries is how often an open is retried after running out of
+	// file descriptors
+	openRetries = 5
+	// openBackoff is the initial delay between retries, doubled each attempt
+	openBackoff = 10 * time.Millisecond
+)
+
+// isTooManyOpenFiles reports whether err is caused by the process or system
+// running out of file descriptors
+func isTooManyOpenFiles(err error) bool {
+	return errors.Is(err, syscall.EMFILE) || errors.Is(err, syscall.ENFILE)
+}
+
+// withOpenRetry runs op and retries with exponential backoff while it fails
+// with a too-many-open-files error
+func withOpenRetry(op func() error) error {
+	delay := openBackoff
+	err := op()
+	for attempt := 0; attempt < openRetries && isTooManyOpenFiles(err); attempt++ {
+		time.Sleep(delay)
+		delay *= 2
+		err = op()
+	}
+	return err
+}
+
+// openFile opens a file for reading, respecting the MaxOpenFiles limit and
+// backing off when file descriptors are exhausted. Files must be released
+// with closeFile.
+func (s *Scanner) openFile(path string) (file, error) {
+	if s.openSem != nil {
+		s.openSem <- struct{}{}
+	}
+
+	var f file
+	err := withOpenRetry(func() error {
+		var err error
+		f, err = s.fs.Open(path)
+		return err
+	})
+	if err != nil {
+		s.releaseOpenSlot()
+		return nil, err
+	}
+	f = &countingFile{file: f, total: &s.progress.BytesRead}
+	if s.limiter != nil {
+		return &throttledFile{file: f, limiter: s.limiter}, nil
+	}
+	return f, nil
+}
+
+func (s *Scanner) closeFile(f file) error {
+	defer s.releaseOpenSlot()
+	return f.Close()
+}
+
+func (s *Scanner) releaseOpenSlot() {
+	if s.openSem != nil {
+		<-s.openSem
+	}
+}
+
+// readDir reads a directory, backing off when file descriptors are exhausted
+func (s *Scanner) readDir(path string) ([]os.DirEntry, error) {
+	var entries []os.DirEntry
+	err := withOpenRetry(func() error {
+		var err error
+		entries, err = s.fs.ReadDir(path)
+		return err
+	})
+	return entries, err
+}
+
+// acquireIO blocks until the caller may perform file I/O. With
+// IOConcurrency set, at most that many workers read file contents at once
+// while the rest keep processing metadata.
+func (s *Scanner) acquireIO() {
+	if s.ioSem != nil {
+		s.ioSem <- struct{}{}
+	}
+}
+
+func (s *Scanner) releaseIO() {
+	if s.ioSem != nil {
+		<-s.ioSem
+	}
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func createFiles(t *testing.T, dir string, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file%02d.txt", i))
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", path, err)
+		}
+	}
+}
+
+func TestTooManyOpenFilesRetry(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 10)
+
+	// Elke eerste open per bestand faalt met EMFILE
+	var mu sync.Mutex
+	failed := make(map[string]bool)
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, WorkerCount: 4})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op != "open" {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !failed[name] {
+			failed[name] = true
+			return &os.PathError{Op: "open", Path: name, Err: syscall.EMFILE}
+		}
+		return nil
+	}}
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	processed := 0
+	for _, file := range result.Files {
+		if file.IsDirectory {
+			continue
+		}
+		processed++
+		if file.AccessError != "" {
+			t.Errorf("Expected %s to be processed after retry, got access error %q", file.Name, file.AccessError)
+		}
+		if file.MimeType == "" {
+			t.Errorf("Expected MIME type to be detected for %s", file.Name)
+		}
+	}
+	if processed != 10 {
+		t.Errorf("Expected 10 processed files, got %d", processed)
+	}
+}
+
+func TestMaxOpenFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 20)
+
+	var open, maxOpen int64
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, WorkerCount: 8, MaxOpenFiles: 2})
+	scanner.fs = &countingFS{open: &open, maxOpen: &maxOpen}
+
+	if _, err := scanner.Scan(tempDir); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if got := atomic.LoadInt64(&maxOpen); got > 2 {
+		t.Errorf("Expected at most 2 files open at once, got %d", got)
+	}
+}
+
+// countingFS tracks how many files are open concurrently
+type countingFS struct {
+	osFS
+	open    *int64
+	maxOpen *int64
+}
+
+type countedFile struct {
+	file
+	open *int64
+}
+
+func (c *countedFile) Close() error {
+	atomic.AddInt64(c.open, -1)
+	return c.file.Close()
+}
+
+func (c *countingFS) Open(name string) (file, error) {
+	f, err := c.osFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	n := atomic.AddInt64(c.open, 1)
+	for {
+		max := atomic.LoadInt64(c.maxOpen)
+		if n <= max || atomic.CompareAndSwapInt64(c.maxOpen, max, n) {
+			break
+		}
+	}
+	return &countedFile{file: f, open: c.open}, nil
+}
+
+func benchmarkIOConcurrency(b *testing.B, ioConcurrency int) {
+	tempDir := b.TempDir()
+	for i := 0; i < 500; i++ {
+		dir := filepath.Join(tempDir, fmt.Sprintf("dir%02d", i%10))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatalf("Failed to create directory: %v", err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("file%03d.txt", i))
+		if err := os.WriteFile(path, make([]byte, 4096), 0644); err != nil {
+			b.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanner := New(models.ScanConfig{
+			MaxFileSizeMB:   10,
+			ScanRecursively: true,
+			WorkerCount:     16,
+			IOConcurrency:   ioConcurrency,
+		})
+		if _, err := scanner.Scan(tempDir); err != nil {
+			b.Fatalf("Scan failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkIOConcurrencySequential(b *testing.B) { benchmarkIOConcurrency(b, 1) }
+func BenchmarkIOConcurrencyUnbounded(b *testing.B)  { benchmarkIOConcurrency(b, 0) }
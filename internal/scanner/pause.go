@@ -0,0 +1,43 @@
+package scanner
+
+// Pause stops workers from starting on new files until Resume is called.
+// Files already being processed are finished and directory walks continue,
+// so pausing never deadlocks the scan.
+func (s *Scanner) Pause() {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	s.paused = true
+}
+
+// Resume lets paused workers continue
+func (s *Scanner) Resume() {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	s.paused = false
+	s.pauseCond.Broadcast()
+}
+
+// IsPaused reports whether the scanner is paused
+func (s *Scanner) IsPaused() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	return s.paused
+}
+
+// Cancel stops a running scan. Workers finish the file they are processing,
+// and Scan returns the partial result together with ErrCancelled. Calling
+// Cancel before Scan makes the scan stop immediately.
+func (s *Scanner) Cancel() {
+	s.cancel()
+	// Paused workers must wake up to observe the cancellation
+	s.Resume()
+}
+
+// waitIfPaused blocks the calling worker while the scanner is paused
+func (s *Scanner) waitIfPaused() {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	for s.paused {
+		s.pauseCond.Wait()
+	}
+}
@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestPauseResume(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 30)
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, WorkerCount: 2})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" {
+			time.Sleep(5 * time.Millisecond)
+		}
+		return nil
+	}}
+
+	type scanOutcome struct {
+		result *models.ScanResult
+		err    error
+	}
+	done := make(chan scanOutcome, 1)
+	go func() {
+		result, err := scanner.Scan(tempDir)
+		done <- scanOutcome{result, err}
+	}()
+
+	// Wacht tot de scan op gang is
+	deadline := time.Now().Add(5 * time.Second)
+	for scanner.GetProgress().ScannedFiles < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("Scan did not start in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	scanner.Pause()
+	// Laat bestanden die al in behandeling zijn afronden
+	time.Sleep(30 * time.Millisecond)
+
+	paused := scanner.GetProgress()
+	if !paused.Paused {
+		t.Error("Expected progress to report the scan as paused")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := scanner.GetProgress().ScannedFiles; got != paused.ScannedFiles {
+		t.Errorf("Expected ScannedFiles to stay at %d while paused, got %d", paused.ScannedFiles, got)
+	}
+	if paused.ScannedFiles >= 30 {
+		t.Fatal("Scan completed before it could be paused")
+	}
+
+	scanner.Resume()
+
+	select {
+	case outcome := <-done:
+		if outcome.err != nil {
+			t.Fatalf("Scan failed: %v", outcome.err)
+		}
+		if outcome.result.Progress.ScannedFiles != 30 {
+			t.Errorf("Expected 30 scanned files after resume, got %d", outcome.result.Progress.ScannedFiles)
+		}
+		if outcome.result.Progress.Paused {
+			t.Error("Expected final progress not to be paused")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scan did not complete after resume")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 50)
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, WorkerCount: 2, BufferSize: 4})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" {
+			time.Sleep(5 * time.Millisecond)
+		}
+		return nil
+	}}
+
+	go func() {
+		for scanner.GetProgress().ScannedFiles < 3 {
+			time.Sleep(time.Millisecond)
+		}
+		scanner.Pause()
+		scanner.Cancel()
+	}()
+
+	done := make(chan struct{})
+	var result *models.ScanResult
+	var err error
+	go func() {
+		defer close(done)
+		result, err = scanner.Scan(tempDir)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cancelled scan did not return")
+	}
+
+	if !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Expected ErrCancelled, got %v", err)
+	}
+	if result == nil || result.Success || result.Error == "" {
+		t.Fatalf("Expected a partial, unsuccessful result, got %+v", result)
+	}
+	if result.Progress.ScannedFiles >= 50 {
+		t.Errorf("Expected the scan to stop early, scanned %d files", result.Progress.ScannedFiles)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package scanner
+
+import "os"
+
+// isWorldWritable is not supported on this platform; Windows reports every
+// writable file as 0666
+func isWorldWritable(info os.FileInfo) bool {
+	return false
+}
@@ -0,0 +1,68 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestWorldWritable(t *testing.T) {
+	tempDir := t.TempDir()
+
+	modes := map[string]os.FileMode{
+		"shared.txt":  0666,
+		"private.txt": 0644,
+	}
+	for name, mode := range modes {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("content"), mode); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+		// Chmod omzeilt de umask
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatalf("Failed to chmod %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		block bool
+	}{
+		{name: "Flag only", block: false},
+		{name: "Flag and block", block: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:      10,
+				FlagWorldWritable:  true,
+				BlockWorldWritable: tt.block,
+			})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			for _, file := range result.Files {
+				if file.IsDirectory {
+					continue
+				}
+				expected := file.Name == "shared.txt"
+				if file.WorldWritable != expected {
+					t.Errorf("%s: expected WorldWritable=%v, got %v", file.Name, expected, file.WorldWritable)
+				}
+				if blocked := tt.block && expected; file.IsBlocked != blocked {
+					t.Errorf("%s: expected blocked=%v, got %v (%s)", file.Name, blocked, file.IsBlocked, file.BlockReason)
+				}
+				if file.IsBlocked && file.BlockReason != "world-writable" {
+					t.Errorf("%s: unexpected block reason %q", file.Name, file.BlockReason)
+				}
+			}
+		})
+	}
+}
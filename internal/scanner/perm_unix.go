@@ -0,0 +1,10 @@
+//go:build unix
+
+package scanner
+
+import "os"
+
+// isWorldWritable reports whether anyone may write to the file
+func isWorldWritable(info os.FileInfo) bool {
+	return info.Mode().Perm()&0002 != 0
+}
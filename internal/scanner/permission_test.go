@@ -0,0 +1,58 @@
+//go:build unix
+
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestPermissionDenied(t *testing.T) {
+	tempDir := t.TempDir()
+
+	locked := filepath.Join(tempDir, "locked")
+	if err := os.Mkdir(locked, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(locked, "secret.txt"), []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "open.txt"), []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Chmod(locked, 0000); err != nil {
+		t.Fatalf("Failed to remove permissions: %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(locked, 0755) })
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, ScanRecursively: true})
+	// Root leest ook onleesbare mappen; bootst de fout dan na
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "readdir" && name == locked && os.Geteuid() == 0 {
+			return &fs.PathError{Op: "open", Path: name, Err: syscall.EACCES}
+		}
+		return nil
+	}}
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if result.Progress.PermissionDenied != 1 {
+		t.Errorf("Expected 1 permission denied, got %d", result.Progress.PermissionDenied)
+	}
+	if len(result.Progress.Errors) != 0 {
+		t.Errorf("Expected no generic errors, got %v", result.Progress.Errors)
+	}
+	for _, file := range result.Files {
+		if file.Path == locked && file.AccessError == "" {
+			t.Error("Expected the locked directory to record its access error")
+		}
+	}
+}
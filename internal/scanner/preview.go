@@ -0,0 +1,33 @@
+package scanner
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"filesystem-logger/internal/models"
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers
+const sniffLen = 512
+
+// textPreview converts the opening bytes of a text file to a string. A rune
+// cut off at the end of data is dropped and any other invalid UTF-8 is
+// removed, so the preview is always safe to embed in JSON.
+func textPreview(data []byte) string {
+	// Drop a trailing rune split by the read limit
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				data = data[:len(data)-i]
+			}
+			break
+		}
+	}
+	return strings.ToValidUTF8(string(data), "")
+}
+
+// isText reports whether file is previewed as text: its content sniffed as
+// text, or its extension is listed in TextExtensions
+func (s *Scanner) isText(file *models.FileInfo) bool {
+	return strings.HasPrefix(file.MimeType, "text/") || s.textTypes[file.Extension]
+}
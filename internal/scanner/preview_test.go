@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestPreview(t *testing.T) {
+	tempDir := t.TempDir()
+
+	text := "Quarterly report\nRevenue grew by 12% compared to last year.\n"
+	testFiles := map[string][]byte{
+		"report.txt": []byte(strings.Repeat(text, 20)),
+		"photo.jpg":  {0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'},
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, PreviewBytes: 64})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		switch file.Name {
+		case "report.txt":
+			if !strings.HasPrefix(file.Preview, "Quarterly report\n") {
+				t.Errorf("Expected preview to hold the opening content, got %q", file.Preview)
+			}
+			if len(file.Preview) > 64 {
+				t.Errorf("Expected preview of at most 64 bytes, got %d", len(file.Preview))
+			}
+		case "photo.jpg":
+			if file.Preview != "" {
+				t.Errorf("Expected no preview for a JPEG, got %q", file.Preview)
+			}
+		}
+	}
+}
+
+func TestTextExtensions(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Een stuurteken laat http.DetectContentType het bestand als binair zien
+	content := "\x01# service config\npassword = hunter2\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "app.conf"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		textExtensions []string
+		expectPreview  bool
+	}{
+		{name: "Detected as binary", expectPreview: false},
+		{name: "Listed as text", textExtensions: []string{".CONF"}, expectPreview: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{MaxFileSizeMB: 10, PreviewBytes: 64, TextExtensions: tt.textExtensions})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			var file models.FileInfo
+			for _, f := range result.Files {
+				if f.Name == "app.conf" {
+					file = f
+				}
+			}
+			if file.MimeType != "application/octet-stream" {
+				t.Fatalf("Expected the content to sniff as binary, got %s", file.MimeType)
+			}
+			matched := strings.Contains(file.Preview, "password")
+			if matched != tt.expectPreview {
+				t.Errorf("Expected keyword in preview=%v, got preview %q", tt.expectPreview, file.Preview)
+			}
+		})
+	}
+}
+
+func TestTextPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "ASCII", data: []byte("hello"), expected: "hello"},
+		{name: "Split rune", data: []byte("caf\xc3"), expected: "caf"},
+		{name: "Complete rune", data: []byte("caf\xc3\xa9"), expected: "café"},
+		{name: "Invalid bytes", data: []byte("a\xffb"), expected: "ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := textPreview(tt.data)
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("Expected valid UTF-8, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"path/filepath"
+
+	"filesystem-logger/internal/models"
+)
+
+// pruneEmptyDirs drops directory entries below the roots that have no file
+// among their descendants in files. The roots themselves are always kept.
+func pruneEmptyDirs(files []models.FileInfo, roots []string) []models.FileInfo {
+	keep := make(map[string]bool, len(roots))
+	for _, root := range roots {
+		keep[root] = true
+	}
+
+	for _, file := range files {
+		if file.IsDirectory {
+			continue
+		}
+		// Mark every ancestor up to the root; stop early once one is known
+		for dir := filepath.Dir(file.Path); !keep[dir]; dir = filepath.Dir(dir) {
+			keep[dir] = true
+			if parent := filepath.Dir(dir); parent == dir {
+				break
+			}
+		}
+	}
+
+	pruned := make([]models.FileInfo, 0, len(files))
+	for _, file := range files {
+		if !file.IsDirectory || keep[file.Path] {
+			pruned = append(pruned, file)
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestPruneEmptyDirs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := []string{
+		"src/main.go",
+		"src/util/helpers.go",
+		"docs/readme.md",
+		"assets/logo.png",
+		"empty/nested/notes.txt",
+	}
+	for _, name := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	// Alleen .go bestanden meenemen
+	includeGo := func(path string, info fs.FileInfo) bool {
+		return info.IsDir() || filepath.Ext(path) == ".go"
+	}
+
+	tests := []struct {
+		name     string
+		prune    bool
+		expected []string
+	}{
+		{
+			name:     "Pruned",
+			prune:    true,
+			expected: []string{"", "src", "src/util"},
+		},
+		{
+			name:     "Not pruned",
+			prune:    false,
+			expected: []string{"", "assets", "docs", "empty", "empty/nested", "src", "src/util"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:   10,
+				ScanRecursively: true,
+				ShouldProcess:   includeGo,
+				PruneEmptyDirs:  tt.prune,
+			})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			var dirs []string
+			for _, file := range result.Files {
+				if file.IsDirectory {
+					rel, _ := filepath.Rel(tempDir, file.Path)
+					if rel == "." {
+						rel = ""
+					}
+					dirs = append(dirs, filepath.ToSlash(rel))
+				}
+			}
+			sort.Strings(dirs)
+
+			if !reflect.DeepEqual(dirs, tt.expected) {
+				t.Errorf("Expected directories %v, got %v", tt.expected, dirs)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"filesystem-logger/internal/models"
+)
+
+// quarantineTarget returns where a blocked file is moved to: its path below
+// the quarantine base, recreated inside QuarantineDir
+func (s *Scanner) quarantineTarget(path string) (string, error) {
+	rel, err := filepath.Rel(s.quarantineBase, s.absPath(path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.config.QuarantineDir, rel), nil
+}
+
+// linkAndRemove moves from to to by hard linking it, which fails atomically
+// when to exists, and then removing from
+func linkAndRemove(from, to string) error {
+	if err := os.Link(from, to); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+// dryRun reports whether quarantining only records destinations, which it
+// does unless DryRun is explicitly set to false
+func (s *Scanner) dryRun() bool {
+	return s.config.DryRun == nil || *s.config.DryRun
+}
+
+// quarantine records where a blocked file would go inside QuarantineDir, or
+// moves it there when DryRun is false. Existing files in the quarantine
+// directory are never overwritten.
+func (s *Scanner) quarantine(file *models.FileInfo) {
+	if s.config.QuarantineDir == "" || !file.IsBlocked || file.IsDirectory || file.Vanished {
+		return
+	}
+
+	target, err := s.quarantineTarget(file.Path)
+	if err != nil {
+		s.recordError(fmt.Errorf("Failed to quarantine %s: %v", file.Path, err))
+		return
+	}
+	if s.dryRun() {
+		file.WouldQuarantineTo = target
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		s.recordError(fmt.Errorf("Failed to quarantine %s: %v", file.Path, err))
+		return
+	}
+	// Het doel wordt atomair gecontroleerd, niet vooraf met Lstat
+	if err := renameNoReplace(file.Path, target); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			s.recordError(fmt.Errorf("Failed to quarantine %s: %s already exists", file.Path, target))
+			return
+		}
+		s.recordError(fmt.Errorf("Failed to quarantine %s: %v", file.Path, err))
+		return
+	}
+	file.QuarantinedTo = target
+}
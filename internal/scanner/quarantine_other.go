@@ -0,0 +1,9 @@
+//go:build !linux
+
+package scanner
+
+// renameNoReplace moves from to to, failing with fs.ErrExist instead of
+// replacing an existing to
+func renameNoReplace(from, to string) error {
+	return linkAndRemove(from, to)
+}
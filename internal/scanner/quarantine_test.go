@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestQuarantine(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name    string
+		dryRun  *bool
+		preview bool
+	}{
+		{name: "Default", dryRun: nil, preview: true},
+		{name: "Dry run", dryRun: &yes, preview: true},
+		{name: "Move", dryRun: &no, preview: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			root := filepath.Join(tempDir, "data")
+			quarantineDir := filepath.Join(tempDir, "quarantine")
+
+			for _, name := range []string{"keep.txt", "bad.exe", "sub/worse.exe"} {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+					t.Fatalf("Failed to create test file %s: %v", name, err)
+				}
+			}
+
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:   10,
+				ScanRecursively: true,
+				BlockedPatterns: []string{"*.exe"},
+				QuarantineDir:   quarantineDir,
+				DryRun:          tt.dryRun,
+			})
+			result, err := scanner.Scan(root)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			expected := map[string]string{
+				filepath.Join(root, "bad.exe"):          filepath.Join(quarantineDir, "bad.exe"),
+				filepath.Join(root, "sub", "worse.exe"): filepath.Join(quarantineDir, "sub", "worse.exe"),
+			}
+			for _, file := range result.Files {
+				target := file.QuarantinedTo
+				if tt.preview {
+					target = file.WouldQuarantineTo
+					if file.QuarantinedTo != "" {
+						t.Errorf("Expected %s not to be quarantined in a dry run", file.Path)
+					}
+				}
+				if target != expected[file.Path] {
+					t.Errorf("Expected %s to be quarantined to %q, got %q", file.Path, expected[file.Path], target)
+				}
+			}
+
+			for source, target := range expected {
+				_, sourceErr := os.Stat(source)
+				_, targetErr := os.Stat(target)
+				if tt.preview && (sourceErr != nil || targetErr == nil) {
+					t.Errorf("Expected %s to stay in place in a dry run", source)
+				}
+				if !tt.preview && (sourceErr == nil || targetErr != nil) {
+					t.Errorf("Expected %s to be moved to %s", source, target)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(root, "keep.txt")); err != nil {
+				t.Errorf("Expected the allowed file to stay in place: %v", err)
+			}
+		})
+	}
+}
+
+func TestQuarantineKeepsExistingTarget(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	quarantineDir := filepath.Join(tempDir, "quarantine")
+	for path, content := range map[string]string{
+		filepath.Join(root, "bad.exe"):          "new",
+		filepath.Join(quarantineDir, "bad.exe"): "old",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", path, err)
+		}
+	}
+
+	dryRun := false
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		BlockedPatterns: []string{"*.exe"},
+		QuarantineDir:   quarantineDir,
+		DryRun:          &dryRun,
+	})
+	result, err := scanner.Scan(root)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		if file.QuarantinedTo != "" {
+			t.Errorf("Expected %s not to be quarantined over an existing file", file.Path)
+		}
+	}
+	if len(result.Progress.Errors) == 0 {
+		t.Error("Expected an error for the existing quarantine target")
+	}
+	if content, _ := os.ReadFile(filepath.Join(quarantineDir, "bad.exe")); string(content) != "old" {
+		t.Errorf("Expected the existing quarantine file to be kept, got %q", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "bad.exe")); string(content) != "new" {
+		t.Errorf("Expected the blocked file to stay in place, got %q", content)
+	}
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket shared by all workers. Tokens are bytes;
+// the bucket refills at rate bytes per second and holds at most one second
+// worth of tokens.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(bytesPerSecond int64) *rateLimiter {
+	return &rateLimiter{
+		rate: float64(bytesPerSecond),
+		last: time.Now(),
+	}
+}
+
+// wait consumes n tokens, sleeping until the bucket has paid off any debt
+func (l *rateLimiter) wait(n int) {
+	if n <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	debt := l.tokens
+	l.mu.Unlock()
+
+	if debt < 0 {
+		time.Sleep(time.Duration(-debt / l.rate * float64(time.Second)))
+	}
+}
+
+// throttledFile charges every read against a shared rate limiter
+type throttledFile struct {
+	file
+	limiter *rateLimiter
+}
+
+func (t *throttledFile) Read(p []byte) (int, error) {
+	n, err := t.file.Read(p)
+	t.limiter.wait(n)
+	return n, err
+}
@@ -0,0 +1,42 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestMaxReadBytesPerSecond(t *testing.T) {
+	tempDir := t.TempDir()
+	for i := 0; i < 10; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("file%02d.bin", i))
+		if err := os.WriteFile(path, make([]byte, 4096), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", path, err)
+		}
+	}
+
+	// 10 bestanden * 512 bytes detectie bij 20 KB/s duurt minstens ~250ms
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:         10,
+		WorkerCount:           4,
+		MaxReadBytesPerSecond: 20000,
+	})
+
+	start := time.Now()
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if result.Progress.ScannedFiles != 10 {
+		t.Errorf("Expected 10 scanned files, got %d", result.Progress.ScannedFiles)
+	}
+	if minimum := 200 * time.Millisecond; elapsed < minimum {
+		t.Errorf("Expected throttled scan to take at least %v, took %v", minimum, elapsed)
+	}
+}
@@ -0,0 +1,27 @@
+package scanner
+
+import "sync/atomic"
+
+// countingFile adds every read to the scan's BytesRead total
+type countingFile struct {
+	file
+	total *int64
+}
+
+func (c *countingFile) Read(p []byte) (int, error) {
+	n, err := c.file.Read(p)
+	atomic.AddInt64(c.total, int64(n))
+	return n, err
+}
+
+// readBudgetExhausted reports whether MaxReadBytes has been read. Once it
+// has, files are no longer opened and the progress records why.
+func (s *Scanner) readBudgetExhausted() bool {
+	if s.config.MaxReadBytes <= 0 || atomic.LoadInt64(&s.progress.BytesRead) < s.config.MaxReadBytes {
+		return false
+	}
+	s.mu.Lock()
+	s.progress.ReadBudgetExhausted = true
+	s.mu.Unlock()
+	return true
+}
@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestMaxReadBytes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("file%d.txt", i))
+		if err := os.WriteFile(path, make([]byte, 1000), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// Elk bestand levert 512 bytes voor de MIME-detectie
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB: 10,
+		MaxReadBytes:  1000,
+		TraversalMode: models.TraversalSequential,
+	})
+	opened := 0
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" {
+			opened++
+		}
+		return nil
+	}}
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if opened != 2 {
+		t.Errorf("Expected 2 files to be opened before the budget ran out, got %d", opened)
+	}
+	if !result.Progress.ReadBudgetExhausted {
+		t.Error("Expected the progress to report the exhausted read budget")
+	}
+	if result.Progress.BytesRead != 1024 {
+		t.Errorf("Expected 1024 bytes read, got %d", result.Progress.BytesRead)
+	}
+
+	skipped := 0
+	for _, file := range result.Files {
+		if file.IsDirectory {
+			continue
+		}
+		if file.Size != 1000 {
+			t.Errorf("Expected the size of %s to be recorded, got %d", file.Name, file.Size)
+		}
+		if file.Note == "not read: read budget exhausted" {
+			skipped++
+			if file.MimeType != "" {
+				t.Errorf("Expected %s not to be sniffed, got %s", file.Name, file.MimeType)
+			}
+		}
+	}
+	if skipped != 3 {
+		t.Errorf("Expected 3 files to be skipped, got %d", skipped)
+	}
+}
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"path/filepath"
+	"sync"
+
+	"filesystem-logger/internal/models"
+)
+
+// realPathCache remembers the resolved form of each directory, so files in
+// the same directory cost no extra syscalls
+type realPathCache struct {
+	mu   sync.Mutex
+	dirs map[string]string
+}
+
+// resolveDir returns dir with all symlinks resolved on fsys
+func (c *realPathCache) resolveDir(fsys fileSystem, dir string) (string, error) {
+	c.mu.Lock()
+	real, ok := c.dirs[dir]
+	c.mu.Unlock()
+	if ok {
+		return real, nil
+	}
+
+	real, err := fsys.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if c.dirs == nil {
+		c.dirs = make(map[string]string)
+	}
+	c.dirs[dir] = real
+	c.mu.Unlock()
+	return real, nil
+}
+
+// setRealPath fills in the canonical path of file when ResolveRealPaths is
+// set. Only the parent directory is resolved, through the cache, unless the
+// file itself is a followed symlink.
+func (s *Scanner) setRealPath(file *models.FileInfo) {
+	if !s.config.ResolveRealPaths {
+		return
+	}
+	path := s.absPath(file.Path)
+	if file.IsSymlink {
+		if real, err := s.fs.EvalSymlinks(path); err == nil {
+			file.RealPath = real
+		}
+		return
+	}
+	if dir, err := s.realPaths.resolveDir(s.fs, filepath.Dir(path)); err == nil {
+		file.RealPath = filepath.Join(dir, filepath.Base(path))
+	}
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+
+	"filesystem-logger/internal/models"
+)
+
+// relativeBase returns the absolute directory that result paths are made
+// relative to: the root directory itself, the parent of a root file, or the
+// deepest directory containing all roots of a glob scan
+func (s *Scanner) relativeBase(roots []string) string {
+	var base string
+	for _, root := range roots {
+		dir := s.absPath(root)
+		if info, err := s.fs.Stat(root); s.explicitPaths || (err == nil && !info.IsDir()) {
+			dir = filepath.Dir(dir)
+		}
+
+		if base == "" {
+			base = dir
+			continue
+		}
+		for !isWithin(dir, base) {
+			base = filepath.Dir(base)
+		}
+	}
+	return base
+}
+
+// isWithin reports whether path equals dir or lies below it
+func isWithin(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	prefix := dir
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, prefix)
+}
+
+// resultPath returns path relative to relBase, or path itself when it
+// cannot be expressed that way
+func (s *Scanner) resultPath(path string) string {
+	rel, err := filepath.Rel(s.relBase, s.absPath(path))
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// relativize rewrites the paths of file relative to relBase
+func (s *Scanner) relativize(file *models.FileInfo) {
+	file.Path = s.resultPath(file.Path)
+	if file.EscapedPath != "" {
+		file.EscapedPath = escapeInvalidUTF8(file.Path)
+	}
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestRelativePaths(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := []string{"notes.txt", "setup.exe", "sub/deep/report.txt", "sub/tool.exe"}
+	for _, name := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:       10,
+		ScanRecursively:     true,
+		BlockedPatterns:     []string{"*.exe"},
+		ExportBlockedToJSON: true,
+		RelativePaths:       true,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if result.Root != tempDir {
+		t.Fatalf("Expected root %s, got %s", tempDir, result.Root)
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range result.Files {
+		if filepath.IsAbs(file.Path) {
+			t.Errorf("Expected a relative path, got %s", file.Path)
+		}
+		// Opnieuw samenvoegen met de root geeft het absolute pad
+		if _, err := os.Stat(filepath.Join(result.Root, file.Path)); err != nil {
+			t.Errorf("Joining %s with the root does not give an existing path: %v", file.Path, err)
+		}
+		seen[filepath.ToSlash(file.Path)] = true
+	}
+	for _, expected := range []string{".", "sub", "sub/deep", "notes.txt", "sub/deep/report.txt"} {
+		if !seen[expected] {
+			t.Errorf("Expected %s in the results", expected)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "blocked_files.json"))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	var exported struct {
+		Root         string            `json:"root"`
+		BlockedFiles []models.FileInfo `json:"blockedFiles"`
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to decode export: %v", err)
+	}
+	if exported.Root != tempDir || len(exported.BlockedFiles) != 2 {
+		t.Fatalf("Expected 2 blocked files under root %s, got %+v", tempDir, exported)
+	}
+	for _, file := range exported.BlockedFiles {
+		if filepath.IsAbs(file.Path) {
+			t.Errorf("Expected a relocatable export path, got %s", file.Path)
+		}
+	}
+}
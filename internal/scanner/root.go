@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+var (
+	// ErrRootNotFound is returned when the scan root does not exist. The
+	// underlying fs.ErrNotExist error stays available to errors.Is.
+	ErrRootNotFound = errors.New("scan root not found")
+
+	// ErrRootNotDirectory is returned when RequireDirRoot is set and the
+	// scan root is not a directory
+	ErrRootNotDirectory = errors.New("scan root is not a directory")
+
+	// ErrUnsupportedRoot is returned for URL-style roots other than
+	// sftp://user@host/path, which only Scan accepts, and for options an
+	// sftp root cannot support
+	ErrUnsupportedRoot = errors.New("unsupported scan root")
+)
+
+// statRoot stats a scan root, translating a missing root into
+// ErrRootNotFound and a non-directory into ErrRootNotDirectory when a
+// directory is required
+func (s *Scanner) statRoot(root string) (fs.FileInfo, error) {
+	if scheme := rootScheme(root); scheme != "" {
+		return nil, fmt.Errorf("%w: %s roots are not supported: %s", ErrUnsupportedRoot, scheme, root)
+	}
+	info, err := s.fs.Stat(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %w", ErrRootNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.config.RequireDirRoot && !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrRootNotDirectory, root)
+	}
+	return info, nil
+}
+
+// rootScheme returns the scheme of a URL-style root, such as "sftp", or ""
+// for a plain path
+func rootScheme(root string) string {
+	scheme, _, ok := strings.Cut(root, "://")
+	if !ok || scheme == "" {
+		return ""
+	}
+	for _, r := range scheme {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '+' || r == '-' || r == '.') {
+			return ""
+		}
+	}
+	return scheme
+}
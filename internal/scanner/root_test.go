@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestRootErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "file.txt")
+	if err := os.WriteFile(file, []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		root           string
+		requireDirRoot bool
+		expected       error
+	}{
+		{name: "Missing root", root: filepath.Join(tempDir, "missing"), expected: ErrRootNotFound},
+		{name: "File root required to be a directory", root: file, requireDirRoot: true, expected: ErrRootNotDirectory},
+		{name: "File root allowed", root: file, expected: nil},
+		{name: "Directory root required", root: tempDir, requireDirRoot: true, expected: nil},
+		{name: "Unsupported remote root", root: "ftp://user@host/data", expected: ErrUnsupportedRoot},
+	}
+
+	for _, tt := range tests {
+		for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+			t.Run(tt.name+"/"+mode, func(t *testing.T) {
+				scanner := New(models.ScanConfig{
+					MaxFileSizeMB:  10,
+					RequireDirRoot: tt.requireDirRoot,
+					TraversalMode:  mode,
+				})
+				_, err := scanner.Scan(tt.root)
+				if tt.expected == nil {
+					if err != nil {
+						t.Errorf("Expected no error, got %v", err)
+					}
+					return
+				}
+				if !errors.Is(err, tt.expected) {
+					t.Errorf("Expected %v, got %v", tt.expected, err)
+				}
+			})
+		}
+	}
+
+	t.Run("Root removed before the walk", func(t *testing.T) {
+		scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+		_, err := scanner.scanRoots([]string{filepath.Join(tempDir, "missing")})
+		if !errors.Is(err, ErrRootNotFound) {
+			t.Errorf("Expected ErrRootNotFound from startScan, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"filesystem-logger/internal/models"
+)
+
+// evaluateRuleGroups checks the rule groups after the verdict of the other
+// checks and records the matching groups in file.MatchedRuleGroups. The
+// first matching group blocks a file that is not blocked yet.
+func (s *Scanner) evaluateRuleGroups(file *models.FileInfo, blocked bool, reason, rule string) (bool, string, string) {
+	if len(s.config.RuleGroups) == 0 || (blocked && !s.config.EvaluateAllRuleGroups) {
+		return blocked, reason, rule
+	}
+
+	for _, group := range s.config.RuleGroups {
+		groupReason, matched := s.matchRuleGroup(group, file)
+		if !matched {
+			continue
+		}
+		file.MatchedRuleGroups = append(file.MatchedRuleGroups, group.Name)
+		if !blocked {
+			blocked = true
+			reason = fmt.Sprintf("Matches rule group %s: %s", group.Name, groupReason)
+			rule = "group:" + group.Name
+		}
+		if !s.config.EvaluateAllRuleGroups {
+			break
+		}
+	}
+	return blocked, reason, rule
+}
+
+// matchRuleGroup reports whether file matches one of the patterns or rules
+// of group, and why
+func (s *Scanner) matchRuleGroup(group models.RuleGroup, file *models.FileInfo) (string, bool) {
+	for _, pattern := range group.BlockedPatterns {
+		matched, err := filepath.Match(s.foldCase(pattern), s.foldCase(file.Name))
+		if err == nil && matched {
+			return fmt.Sprintf("pattern %s", pattern), true
+		}
+	}
+	for _, rule := range group.Rules {
+		if blocked, reason := rule.Evaluate(file); blocked {
+			return reason, true
+		}
+	}
+	return "", false
+}
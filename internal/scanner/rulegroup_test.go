@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestRuleGroups(t *testing.T) {
+	groups := []models.RuleGroup{
+		{Name: "executables", BlockedPatterns: []string{"*.exe"}},
+		{Name: "installers", Rules: []models.BlockRule{models.BlockRuleFunc(func(file *models.FileInfo) (bool, string) {
+			return strings.HasPrefix(file.Name, "setup"), "installer name"
+		})}},
+		{Name: "archives", BlockedPatterns: []string{"*.zip"}},
+	}
+
+	tests := []struct {
+		name       string
+		exhaustive bool
+		file       models.FileInfo
+		blocked    bool
+		reason     string
+		matched    []string
+	}{
+		{
+			name:       "Exhaustive records every group",
+			exhaustive: true,
+			file:       models.FileInfo{Name: "setup.exe", Extension: ".exe"},
+			blocked:    true,
+			reason:     "Matches rule group executables: pattern *.exe",
+			matched:    []string{"executables", "installers"},
+		},
+		{
+			name:    "Short-circuit stops at the first group",
+			file:    models.FileInfo{Name: "setup.exe", Extension: ".exe"},
+			blocked: true,
+			reason:  "Matches rule group executables: pattern *.exe",
+			matched: []string{"executables"},
+		},
+		{
+			name:    "No matching group",
+			file:    models.FileInfo{Name: "notes.txt", Extension: ".txt"},
+			matched: nil,
+		},
+		{
+			name:       "Exhaustive after a built-in block",
+			exhaustive: true,
+			file:       models.FileInfo{Name: "setup.zip", Extension: ".zip", Size: 2 * 1024 * 1024},
+			blocked:    true,
+			reason:     "File size exceeds limit",
+			matched:    []string{"installers", "archives"},
+		},
+		{
+			name:    "Short-circuit after a built-in block",
+			file:    models.FileInfo{Name: "setup.zip", Extension: ".zip", Size: 2 * 1024 * 1024},
+			blocked: true,
+			reason:  "File size exceeds limit",
+			matched: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{MaxFileSizeMB: 1, RuleGroups: groups, EvaluateAllRuleGroups: tt.exhaustive})
+			file := tt.file
+			blocked, reason := scanner.evaluateBlock(&file)
+			if blocked != tt.blocked {
+				t.Errorf("Expected blocked=%v, got %v (%s)", tt.blocked, blocked, reason)
+			}
+			if !strings.HasPrefix(reason, tt.reason) {
+				t.Errorf("Expected reason starting with %q, got %q", tt.reason, reason)
+			}
+			if !reflect.DeepEqual(file.MatchedRuleGroups, tt.matched) {
+				t.Errorf("Expected matched groups %v, got %v", tt.matched, file.MatchedRuleGroups)
+			}
+		})
+	}
+}
+
+func TestRuleGroupsScan(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"setup.exe", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:         10,
+		EvaluateAllRuleGroups: true,
+		RuleGroups: []models.RuleGroup{
+			{Name: "executables", BlockedPatterns: []string{"*.exe"}},
+			{Name: "setup", BlockedPatterns: []string{"setup.*"}},
+		},
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		switch file.Name {
+		case "setup.exe":
+			if !file.IsBlocked || !reflect.DeepEqual(file.MatchedRuleGroups, []string{"executables", "setup"}) {
+				t.Errorf("Expected setup.exe blocked by both groups, got blocked=%v groups=%v", file.IsBlocked, file.MatchedRuleGroups)
+			}
+		case "notes.txt":
+			if file.IsBlocked || len(file.MatchedRuleGroups) != 0 {
+				t.Errorf("Expected notes.txt to match no group, got %v", file.MatchedRuleGroups)
+			}
+		}
+	}
+
+	t.Run("Invalid pattern", func(t *testing.T) {
+		scanner := New(models.ScanConfig{RuleGroups: []models.RuleGroup{{Name: "broken", BlockedPatterns: []string{"[a-"}}}})
+		if _, err := scanner.Scan(tempDir); err == nil || !strings.Contains(err.Error(), "broken") {
+			t.Errorf("Expected an error naming the rule group, got %v", err)
+		}
+	})
+}
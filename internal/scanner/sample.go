@@ -0,0 +1,25 @@
+package scanner
+
+import (
+	"sync/atomic"
+)
+
+// sampledOut counts a file with extension ext and reports whether it falls
+// beyond MaxPerExtension and should be skipped. Each extension has its own
+// atomic counter so workers only contend on files of the same type.
+func (s *Scanner) sampledOut(ext string) bool {
+	if s.config.MaxPerExtension <= 0 {
+		return false
+	}
+
+	counter, ok := s.extensionCounts.Load(ext)
+	if !ok {
+		counter, _ = s.extensionCounts.LoadOrStore(ext, new(int64))
+	}
+	if atomic.AddInt64(counter.(*int64), 1) <= int64(s.config.MaxPerExtension) {
+		return false
+	}
+
+	atomic.AddInt64(&s.progress.SampledOut, 1)
+	return true
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestMaxPerExtension(t *testing.T) {
+	tempDir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("photo%02d.jpg", i))
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("notes%d.txt", i))
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+		t.Run(mode, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:   10,
+				MaxPerExtension: 5,
+				TraversalMode:   mode,
+			})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			counts := make(map[string]int)
+			var size int64
+			for _, file := range result.Files {
+				if !file.IsDirectory {
+					counts[file.Extension]++
+					size += file.Size
+				}
+			}
+			if counts[".jpg"] != 5 {
+				t.Errorf("Expected 5 .jpg files, got %d", counts[".jpg"])
+			}
+			if counts[".txt"] != 2 {
+				t.Errorf("Expected both .txt files, got %d", counts[".txt"])
+			}
+			if result.Progress.SampledOut != 15 {
+				t.Errorf("Expected 15 sampled out files, got %d", result.Progress.SampledOut)
+			}
+			// Totalen tellen alle gevonden bestanden, ook de overgeslagen
+			if expected := int64(len(result.Files)) + result.Progress.SampledOut; result.Progress.TotalFiles != expected {
+				t.Errorf("Expected TotalFiles=%d including sampled out files, got %d", expected, result.Progress.TotalFiles)
+			}
+			if expected := size + 15*4; result.Progress.TotalSize != expected {
+				t.Errorf("Expected TotalSize=%d including sampled out files, got %d", expected, result.Progress.TotalSize)
+			}
+		})
+	}
+}
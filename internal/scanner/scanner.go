@@ -1,29 +1,145 @@
 package scanner
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"filesystem-logger/internal/models"
 	"filesystem-logger/internal/utils/jsonexport"
 )
 
+// ErrCancelled is returned by Scan, together with the partial result, when
+// the scan was stopped with Cancel
+var ErrCancelled = errors.New("scan cancelled")
+
 type Scanner struct {
-	config     models.ScanConfig
-	progress   *models.ScanProgress
-	mu         sync.Mutex
-	workChan   chan models.ScanWork
-	resultChan chan models.ScanWorkResult
-	errorChan  chan error
-	doneChan   chan struct{}
-	dirWg      sync.WaitGroup
+	config        models.ScanConfig
+	progress      *models.ScanProgress
+	mu            sync.Mutex
+	workChan      chan models.ScanWork
+	resultChan    chan models.ScanWorkResult
+	errorChan     chan error
+	doneChan      chan struct{}
+	dirWg         sync.WaitGroup
+	rules         []models.BlockRule
+	allowedTypes  map[string]bool
+	textTypes     map[string]bool
+	trustedTypes  map[string]bool
+	fileTypeNames map[string]string
+	categories    map[string]string
+	fs            fileSystem
+	openSem       chan struct{}
+	ioSem         chan struct{}
+	limiter       *rateLimiter
+
+	// badHashes holds the lowercase hex hashes from HashBlocklistPath
+	badHashes map[string]struct{}
+	// selfPaths holds the absolute paths of files and directories the scan
+	// writes itself. It is filled before the walk starts.
+	selfPaths map[string]struct{}
+	cwd       string
+
+	// explicitPaths is set by ScanPaths: roots are processed as single
+	// entries and never walked
+	explicitPaths bool
+
+	// quarantineBase is the absolute directory whose layout is recreated
+	// inside QuarantineDir
+	quarantineBase string
+
+	// relBase is the absolute directory result paths are made relative to
+	// when RelativePaths is set
+	relBase string
+
+	// activeWorkers counts the workers that have not exited yet
+	activeWorkers int32
+
+	// abandoned is set by abandonScan; the collector then drops the results
+	// of the remaining workers. collectMu is held while a result is
+	// collected, so no result is half processed once abandoned is set.
+	collectMu sync.Mutex
+	abandoned bool
+
+	// workerStats are merged in by workers as they exit, guarded by mu
+	workerStats []models.WorkerStat
+
+	// checkpoint records completed directories when CheckpointPath is set
+	checkpoint *checkpoint
+
+	// decisions logs every verdict when DecisionLogPath is set
+	decisions *decisionLog
+
+	// incremental appends blocked files when IncrementalExportPath is set
+	incremental *incrementalExport
+
+	// state compares files with the previous run when StatePath is set
+	state *scanState
+
+	// shape tracks the tree metrics reported in the result, guarded by mu
+	shape treeShape
+
+	// slowest holds the files that took longest to process, slowest first,
+	// guarded by mu
+	slowest []models.FileTiming
+
+	// extensionCounts maps each extension to an *int64 counting its
+	// processed files when MaxPerExtension is set
+	extensionCounts sync.Map
+
+	// linkTargets records the files counted when FollowSymlinkFiles is set,
+	// guarded by mu
+	linkTargets map[fileKey]bool
+
+	// realPaths caches resolved directories when ResolveRealPaths is set
+	realPaths realPathCache
+
+	// scanErrors holds the errors behind progress.Errors, guarded by mu
+	scanErrors []error
+
+	// keyStyle is the parsed ExportKeyStyle
+	keyStyle jsonexport.KeyStyle
+
+	// newHash constructs the hash selected by HashAlgorithm
+	newHash func() hash.Hash
+
+	// initErr records a configuration error found by New; Scan returns it
+	initErr error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	pauseMu   sync.Mutex
+	pauseCond *sync.Cond
+	paused    bool
+
+	// files collected so far, guarded by mu
+	files []models.FileInfo
+
+	// sink receives collected files; Scan uses the in-memory sink
+	sink ResultSink
+	// sinkErr is the first error returned by the sink's Add
+	sinkErr error
+
+	// omittedBlocked holds blocked files left out of the result when
+	// OmitBlocked is set or results are streamed to a file, so they can
+	// still be exported
+	omittedBlocked []models.FileInfo
 }
 
 func New(config models.ScanConfig) *Scanner {
@@ -33,79 +149,475 @@ func New(config models.ScanConfig) *Scanner {
 	if config.BufferSize <= 0 {
 		config.BufferSize = 1000 // default buffer size
 	}
+	if config.WorkBufferSize <= 0 {
+		config.WorkBufferSize = config.BufferSize
+	}
+	if config.ResultBufferSize <= 0 {
+		config.ResultBufferSize = config.BufferSize
+	}
+	if config.ProgressInterval <= 0 {
+		config.ProgressInterval = 250 * time.Millisecond
+	}
+	if config.CancelTimeout <= 0 {
+		config.CancelTimeout = 5 * time.Second
+	}
+	if config.HashBlocklistPath != "" {
+		config.ComputeHash = true // the blocklist needs file hashes
+	}
 
-	return &Scanner{
-		config:     config,
-		progress:   &models.ScanProgress{StartTime: time.Now()},
-		workChan:   make(chan models.ScanWork, config.BufferSize),
-		resultChan: make(chan models.ScanWorkResult, config.BufferSize),
-		errorChan:  make(chan error, config.BufferSize),
-		doneChan:   make(chan struct{}),
+	s := &Scanner{
+		config:        config,
+		rules:         append([]models.BlockRule(nil), config.Rules...),
+		allowedTypes:  buildTypeSet(config.AllowedTypes),
+		textTypes:     buildTypeSet(config.TextExtensions),
+		trustedTypes:  buildTypeSet(config.TrustExtensionFor),
+		fileTypeNames: lowerKeys(config.FileTypeNames),
+		categories:    lowerKeys(config.Categories),
+		progress:      &models.ScanProgress{StartTime: time.Now()},
+		workChan:      make(chan models.ScanWork, config.WorkBufferSize),
+		resultChan:    make(chan models.ScanWorkResult, config.ResultBufferSize),
+		errorChan:     make(chan error, config.BufferSize),
+		doneChan:      make(chan struct{}),
+		fs:            osFS{},
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.pauseCond = sync.NewCond(&s.pauseMu)
+	s.sink = memorySink{s}
+	if config.MaxOpenFiles > 0 {
+		s.openSem = make(chan struct{}, config.MaxOpenFiles)
+	}
+	if config.IOConcurrency > 0 {
+		s.ioSem = make(chan struct{}, config.IOConcurrency)
+	}
+	if config.MaxReadBytesPerSecond > 0 {
+		s.limiter = newRateLimiter(config.MaxReadBytesPerSecond)
+	}
+	if config.HashBlocklistPath != "" {
+		s.badHashes, s.initErr = loadHashBlocklist(config.HashBlocklistPath)
+	}
+	if newHash, err := newHasher(config.HashAlgorithm); err != nil {
+		s.initErr = err
+	} else {
+		s.newHash = newHash
+	}
+	if algorithm := hashAlgorithmName(config.HashAlgorithm); config.HashBlocklistPath != "" && algorithm != models.HashSHA256 {
+		s.initErr = fmt.Errorf("hash blocklist requires the %s hash algorithm, not %s", models.HashSHA256, algorithm)
+	}
+	if err := validatePatterns(config.BlockedPatterns); err != nil {
+		s.initErr = err
+	}
+	if err := validateAllowlist(config.AllowlistPaths); err != nil {
+		s.initErr = err
+	}
+	for _, group := range config.RuleGroups {
+		if err := validatePatterns(group.BlockedPatterns); err != nil {
+			s.initErr = fmt.Errorf("rule group %s: %w", group.Name, err)
+		}
+	}
+	if style, err := jsonexport.ParseKeyStyle(config.ExportKeyStyle); err != nil {
+		s.initErr = err
+	} else {
+		s.keyStyle = style
 	}
+	switch config.TraversalMode {
+	case "", models.TraversalParallel, models.TraversalSequential:
+	default:
+		s.initErr = fmt.Errorf("unknown traversal mode %q", config.TraversalMode)
+	}
+
+	return s
 }
 
+// AddRule registers a custom block rule. Rules are evaluated in order after
+// the built-in checks and must be added before Scan is called.
+func (s *Scanner) AddRule(rule models.BlockRule) {
+	s.rules = append(s.rules, rule)
+}
+
+// Scan scans root and returns the collected result. Root may be a directory
+// or a single regular file, in which case the result holds just that file,
+// unless RequireDirRoot is set. A missing root yields ErrRootNotFound.
+//
+// Root may also name a remote directory as sftp://user@host[:port]/path;
+// it is then walked over SFTP and the result holds the remote paths.
 func (s *Scanner) Scan(root string) (*models.ScanResult, error) {
+	if s.initErr != nil {
+		return nil, s.initErr
+	}
 	if root == "" {
 		return nil, fmt.Errorf("empty path provided")
 	}
 
-	if _, err := os.Stat(root); err != nil {
+	if rootScheme(root) == "sftp" {
+		remote, dir, err := s.openRemoteRoot(root)
+		if err != nil {
+			return nil, err
+		}
+		defer remote.Close()
+		root = dir
+	}
+
+	if _, err := s.statRoot(root); err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	return s.scanRoots([]string{root})
+}
 
-	// Start result collector first
-	resultDone := make(chan struct{})
-	var result models.ScanResult
-	go s.collectResults(&result, resultDone)
+// ScanDir scans root with config in a single call. Use New when the scan
+// needs to be paused, cancelled or watched while it runs.
+func ScanDir(root string, config models.ScanConfig) (*models.ScanResult, error) {
+	return New(config).Scan(root)
+}
+
+// ScanGlob expands pattern with filepath.Glob and scans every matching file
+// or directory into one combined result. Blocked files are exported next to
+// the first match.
+func (s *Scanner) ScanGlob(pattern string) (*models.ScanResult, error) {
+	if s.initErr != nil {
+		return nil, s.initErr
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern provided")
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %v", pattern, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no paths match pattern %q", pattern)
+	}
+
+	for _, match := range matches {
+		info, err := s.statRoot(match)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%s is not a file or directory", match)
+		}
+	}
+
+	return s.scanRoots(matches)
+}
+
+// ScanPaths processes exactly the given file paths, for example a list
+// produced by find or git ls-files, without walking any directory. All
+// detection and blocking rules apply. Paths that do not exist are reported
+// as vanished, and directories are listed but not descended into. Blocked
+// files are exported next to the first path.
+func (s *Scanner) ScanPaths(paths []string) (*models.ScanResult, error) {
+	if s.initErr != nil {
+		return nil, s.initErr
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths provided")
+	}
+
+	s.explicitPaths = true
+	return s.scanRoots(paths)
+}
+
+func (s *Scanner) scanRoots(roots []string) (*models.ScanResult, error) {
+	ctx := s.ctx
+	defer s.cancel()
+
+	// Stream results to disk instead of keeping them in memory
+	var stream *bufio.Writer
+	if s.config.StreamToFile != "" {
+		f, err := os.Create(s.config.StreamToFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stream file: %v", err)
+		}
+		defer f.Close()
+		stream = bufio.NewWriter(f)
+	}
+
+	s.cwd, _ = os.Getwd()
+	roots = s.uniqueRoots(roots)
+	if s.config.RelativePaths {
+		s.relBase = s.relativeBase(roots)
+	}
+
+	// Never report the files this scan writes itself
+	if s.config.ExportBlockedToJSON {
+		s.addSelfPath(filepath.Join(s.exportDir(roots[0]), "blocked_files.json"))
+	}
+	if s.config.StreamToFile != "" {
+		s.addSelfPath(s.config.StreamToFile)
+	}
+	if s.config.QuarantineDir != "" {
+		s.addSelfPath(s.config.QuarantineDir)
+		s.quarantineBase = s.relativeBase(roots)
+	}
 
-	// Start worker pool
-	var wg sync.WaitGroup
-	for i := 0; i < s.config.WorkerCount; i++ {
-		wg.Add(1)
-		go s.worker(ctx, &wg)
+	if s.config.StatePath != "" {
+		s.addSelfPath(s.config.StatePath)
+		st, err := loadState(s.config.StatePath)
+		if err != nil {
+			return nil, err
+		}
+		s.state = st
 	}
 
-	// Start initial directory walk
-	s.dirWg.Add(1)
-	if err := s.startScan(root); err != nil {
-		return nil, fmt.Errorf("scan error: %v", err)
+	if s.config.CheckpointPath != "" {
+		s.addSelfPath(s.config.CheckpointPath)
+		cp, err := openCheckpoint(s.config.CheckpointPath, s.config.ResumeFromCheckpoint)
+		if err != nil {
+			return nil, err
+		}
+		s.checkpoint = cp
 	}
 
-	// Create a separate goroutine to close workChan after initial scan
-	go func() {
-		s.dirWg.Wait()
-		close(s.workChan)
-	}()
+	if s.config.DecisionLogPath != "" {
+		s.addSelfPath(s.config.DecisionLogPath)
+		decisions, err := openDecisionLog(s.config.DecisionLogPath)
+		if err != nil {
+			s.checkpoint.close(false)
+			return nil, err
+		}
+		s.decisions = decisions
+	}
 
-	// Wait for all workers to finish
-	wg.Wait()
+	if s.config.IncrementalExportPath != "" {
+		s.addSelfPath(s.config.IncrementalExportPath)
+		incremental, err := openIncrementalExport(s.config.IncrementalExportPath)
+		if err != nil {
+			s.checkpoint.close(false)
+			s.decisions.close()
+			return nil, err
+		}
+		s.incremental = incremental
+	}
+
+	// Start result and error collectors first
+	resultDone := make(chan struct{})
+	var result models.ScanResult
+	go s.collectResults(&result, stream, resultDone)
+	errorsDone := make(chan struct{})
+	go s.collectErrors(errorsDone)
+
+	// scanErr fails the scan once the workers and collectors are stopped
+	var scanErr error
+	workersDone := make(chan struct{})
+	if s.sequential() {
+		// The walk runs on a goroutine of its own so CancelTimeout also
+		// bounds a walk stuck in a system call
+		var walkErr error
+		atomic.AddInt32(&s.activeWorkers, 1)
+		go func() {
+			defer close(workersDone)
+			defer atomic.AddInt32(&s.activeWorkers, -1)
+			walkErr = s.walkSequential(ctx, roots)
+		}()
+
+		if !s.awaitWorkers(ctx, workersDone) {
+			return s.abandonScan(stream, workersDone, resultDone, errorsDone)
+		}
+		scanErr = walkErr
+	} else {
+		// Start worker pool
+		var wg sync.WaitGroup
+		for i := 0; i < s.config.WorkerCount; i++ {
+			wg.Add(1)
+			go s.worker(ctx, &wg, i)
+		}
+
+		// Queue every root before the closer goroutine starts waiting
+		for _, root := range roots {
+			if err := s.startScan(root); err != nil {
+				// Stop the walks of the roots queued so far
+				scanErr = fmt.Errorf("scan error: %w", err)
+				s.cancel()
+				break
+			}
+		}
 
-	// Close result channel and wait for collector to finish
+		// Create a separate goroutine to close workChan after initial scan
+		go func() {
+			s.dirWg.Wait()
+			close(s.workChan)
+		}()
+
+		go func() {
+			// Wait for all workers to finish
+			wg.Wait()
+
+			// Release root walks that were still queued when the scan was
+			// cancelled. The range ends once every directory walk is done
+			// and workChan is closed.
+			for work := range s.workChan {
+				if work.IsDir {
+					s.dirWg.Done()
+				}
+			}
+			close(workersDone)
+		}()
+
+		if !s.awaitWorkers(ctx, workersDone) {
+			result, err := s.abandonScan(stream, workersDone, resultDone, errorsDone)
+			if scanErr != nil {
+				return nil, scanErr
+			}
+			return result, err
+		}
+	}
+
+	// Close result and error channels and wait for the collectors to finish
 	close(s.resultChan)
+	close(s.errorChan)
 	<-resultDone
+	<-errorsDone
+
+	if stream != nil {
+		if err := stream.Flush(); err != nil {
+			s.recordError(fmt.Errorf("Failed to write stream file: %v", err))
+		}
+	}
+	if err := s.checkpoint.close(ctx.Err() == nil && scanErr == nil); err != nil {
+		s.recordError(err)
+	}
+	if err := s.decisions.close(); err != nil {
+		s.recordError(err)
+	}
+	// A failed scan keeps the previous state and ends the incremental
+	// export as incomplete
+	if scanErr != nil {
+		progress := s.GetProgress()
+		if err := s.incremental.finish(*progress, time.Since(progress.StartTime), false); err != nil {
+			log.Printf("scanner: %v", err)
+		}
+		return nil, scanErr
+	}
+	// A cancelled scan saw only part of the tree; keep the previous state
+	if ctx.Err() == nil {
+		s.reportDeleted(&result, roots)
+		if err := s.state.save(s.config.StatePath); err != nil {
+			s.recordError(err)
+		}
+	}
+
+	if s.config.PruneEmptyDirs {
+		pruneRoots := roots
+		if s.relBase != "" {
+			pruneRoots = make([]string, len(roots))
+			for i, root := range roots {
+				pruneRoots[i] = s.resultPath(root)
+			}
+		}
+		result.Files = pruneEmptyDirs(result.Files, pruneRoots)
+	}
+	if s.config.AggregateDirSizes {
+		models.AggregateDirSizes(result.Files)
+	}
+	result.Root = s.relBase
+	if s.config.ComputeHash || s.config.QuickHash {
+		result.HashAlgorithm = hashAlgorithmName(s.config.HashAlgorithm)
+	}
+	s.applyShape(&result)
+	s.applySlowest(&result)
+
+	if s.config.CollectWorkerStats {
+		sort.Slice(s.workerStats, func(i, j int) bool {
+			return s.workerStats[i].Worker < s.workerStats[j].Worker
+		})
+		result.PerWorkerStats = s.workerStats
+	}
 
 	result.Duration = time.Since(s.progress.StartTime)
-	result.Progress = *s.progress
-	result.Success = len(result.Progress.Errors) == 0
+	result.DurationHuman = result.Duration.String()
+	if err := s.incremental.finish(*s.GetProgress(), result.Duration, ctx.Err() == nil); err != nil {
+		s.recordError(err)
+	}
+	result.Progress = *s.GetProgress()
+	result.Success = len(s.scanErrors) == 0
+
+	// A cancelled scan returns its partial result without exporting it
+	if ctx.Err() != nil {
+		result.Success = false
+		result.Error = ErrCancelled.Error()
+		return &result, ErrCancelled
+	}
 
 	// Export blocked files if configured
 	if s.config.ExportBlockedToJSON {
-		exportPath := filepath.Join(root, "blocked_files.json")
-		if err := jsonexport.ExportBlockedFiles(&result, exportPath); err != nil {
+		exportResult := result
+		if s.config.OmitBlocked || s.config.StreamToFile != "" {
+			exportResult.Files = s.omittedBlocked
+		}
+		exportPath := filepath.Join(s.exportDir(roots[0]), "blocked_files.json")
+		if err := jsonexport.ExportBlockedFilesWithStyle(&exportResult, exportPath, s.keyStyle); err != nil {
 			// Log the error but don't fail the scan
-			result.Progress.Errors = append(result.Progress.Errors,
-				fmt.Sprintf("Failed to export blocked files: %v", err))
+			err = fmt.Errorf("Failed to export blocked files: %v", err)
+			result.Progress.Errors = append(result.Progress.Errors, err.Error())
+			s.scanErrors = append(s.scanErrors, err)
 		}
 	}
 
+	if s.config.FailOnError && len(s.scanErrors) > 0 {
+		return &result, fmt.Errorf("scan completed with %d errors: %w",
+			len(s.scanErrors), errors.Join(s.scanErrors...))
+	}
+
 	return &result, nil
 }
 
-func (s *Scanner) worker(ctx context.Context, wg *sync.WaitGroup) {
+// collectErrors records the errors reported by directory walks
+func (s *Scanner) collectErrors(done chan<- struct{}) {
+	defer close(done)
+	for err := range s.errorChan {
+		s.recordError(err)
+	}
+}
+
+// recordError adds err to the progress errors. Permission errors are only
+// counted in PermissionDenied; FailOnError still sees them.
+func (s *Scanner) recordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.countPermissionDenied(err) {
+		s.progress.Errors = append(s.progress.Errors, err.Error())
+	}
+	s.scanErrors = append(s.scanErrors, err)
+}
+
+// countPermissionDenied increments PermissionDenied when err is a
+// permission error and reports whether it was one
+func (s *Scanner) countPermissionDenied(err error) bool {
+	if !errors.Is(err, fs.ErrPermission) {
+		return false
+	}
+	atomic.AddInt64(&s.progress.PermissionDenied, 1)
+	return true
+}
+
+// exportDir returns the directory the blocked file export is written to
+func (s *Scanner) exportDir(root string) string {
+	if s.explicitPaths {
+		return filepath.Dir(root)
+	}
+	if info, err := s.fs.Stat(root); err == nil && !info.IsDir() {
+		return filepath.Dir(root)
+	}
+	return root
+}
+
+func (s *Scanner) worker(ctx context.Context, wg *sync.WaitGroup, index int) {
 	defer wg.Done()
+	atomic.AddInt32(&s.activeWorkers, 1)
+	defer atomic.AddInt32(&s.activeWorkers, -1)
+
+	// Counted locally and merged once to keep workers from contending
+	var stat *models.WorkerStat
+	if s.config.CollectWorkerStats {
+		stat = &models.WorkerStat{Worker: index}
+		defer func() {
+			s.mu.Lock()
+			s.workerStats = append(s.workerStats, *stat)
+			s.mu.Unlock()
+		}()
+	}
 
 	for {
 		select {
@@ -115,44 +627,127 @@ func (s *Scanner) worker(ctx context.Context, wg *sync.WaitGroup) {
 			if !ok {
 				return
 			}
-			s.processWork(ctx, work)
+			s.processWork(ctx, work, stat)
 		}
 	}
 }
 
-func (s *Scanner) processWork(ctx context.Context, work models.ScanWork) {
+// processWork scans a directory or a single file. Scanned files are counted
+// in stat when it is not nil.
+func (s *Scanner) processWork(ctx context.Context, work models.ScanWork, stat *models.WorkerStat) {
 	if work.IsDir {
-		s.scanDirectory(ctx, work.Path, work.Path)
+		rootInfo := models.FileInfo{
+			Path:        work.Path,
+			Name:        filepath.Base(work.Path),
+			IsDirectory: true,
+			EntryType:   models.EntryDir,
+		}
+		s.scanDirectory(ctx, rootInfo, work.Path, nil)
 		return
 	}
 
+	if s.checkpoint != nil {
+		defer s.checkpoint.release(filepath.Dir(s.absPath(work.Path)))
+	}
+
+	// Open geen nieuwe bestanden zolang de scan gepauzeerd is
+	s.waitIfPaused()
+	if s.config.ReportSlowestFiles > 0 {
+		defer s.recordTiming(work.Path, time.Now())
+	}
+
 	fileInfo := models.FileInfo{
 		Path: work.Path,
 		Name: filepath.Base(work.Path),
 	}
+	if !utf8.ValidString(fileInfo.Path) {
+		fileInfo.InvalidName = !utf8.ValidString(fileInfo.Name)
+		fileInfo.EscapedPath = escapeInvalidUTF8(fileInfo.Path)
+	}
+	fileInfo.SuspiciousName = isSuspiciousName(fileInfo.Name)
+	if s.config.FollowSymlinkFiles {
+		if link, err := s.fs.Lstat(work.Path); err == nil {
+			fileInfo.IsSymlink = link.Mode()&os.ModeSymlink != 0
+		}
+	}
 
-	info, err := os.Stat(work.Path)
+	info, err := s.fs.Stat(work.Path)
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			s.reportVanished(fileInfo)
+			return
+		}
+		// Overly long paths often can't be stat'ed; report them as blocked
+		// instead of failing
+		if s.isPathTooLong(work.Path) {
+			fileInfo.AccessError = err.Error()
+			s.judge(&fileInfo, work.Root)
+			atomic.AddInt64(&s.progress.ScannedFiles, 1)
+			if fileInfo.IsBlocked {
+				atomic.AddInt64(&s.progress.BlockedFiles, 1)
+			}
+			countWork(stat, fileInfo.Size)
+			s.resultChan <- models.ScanWorkResult{FileInfo: fileInfo}
+			return
+		}
 		s.resultChan <- models.ScanWorkResult{FileInfo: fileInfo, Error: err}
 		return
 	}
 
 	fileInfo.Size = info.Size()
 	fileInfo.ModTime = info.ModTime()
+	fileInfo.CreatedTime = creationTime(info)
 	fileInfo.IsDirectory = info.IsDir()
 	fileInfo.Extension = strings.ToLower(filepath.Ext(info.Name()))
+	fileInfo.EntryType = entryType(info.Mode())
+	if fileInfo.EntryType == models.EntryFile && s.sampledOut(fileInfo.Extension) {
+		s.state.skip(s.absPath(work.Path))
+		return
+	}
+	s.countSpecial(fileInfo.EntryType)
+	s.setRealPath(&fileInfo)
+	if fileInfo.ModTime.After(s.progress.StartTime) {
+		atomic.AddInt64(&s.progress.ModifiedDuringScan, 1)
+	}
+	if fileInfo.EntryType == models.EntryFile && fileInfo.Size == 0 {
+		fileInfo.IsEmpty = true
+		atomic.AddInt64(&s.progress.EmptyFiles, 1)
+	}
+	if s.config.FlagWorldWritable {
+		fileInfo.WorldWritable = isWorldWritable(info)
+	}
 
-	if err := s.detectFileType(&fileInfo); err != nil {
-		fileInfo.AccessError = err.Error()
+	if s.config.DetectHardLinks {
+		if id, linked := hardLinkID(info); linked {
+			fileInfo.Device = id.dev
+			fileInfo.Inode = id.ino
+		}
 	}
 
-	if s.shouldBlockFile(&fileInfo) {
-		fileInfo.IsBlocked = true
-		fileInfo.BlockReason = s.getBlockReason(&fileInfo)
+	// Devices, sockets and pipes are never opened; reading a pipe may block
+	if fileInfo.EntryType == models.EntryFile && s.readBudgetExhausted() {
+		fileInfo.Note = "not read: read budget exhausted"
+	} else if fileInfo.EntryType == models.EntryFile {
+		if err := s.detectFileType(&fileInfo); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				s.reportVanished(fileInfo)
+				return
+			}
+			s.countPermissionDenied(err)
+			fileInfo.AccessError = err.Error()
+		}
 	}
 
+	s.judge(&fileInfo, work.Root)
+
 	atomic.AddInt64(&s.progress.ScannedFiles, 1)
-	atomic.AddInt64(&s.progress.ScannedSize, fileInfo.Size)
+	if s.config.FollowSymlinkFiles && fileInfo.EntryType == models.EntryFile {
+		fileInfo.IsDuplicateLink = s.isDuplicateLink(fileInfo.IsSymlink, info)
+	}
+	if !fileInfo.IsDuplicateLink {
+		atomic.AddInt64(&s.progress.ScannedSize, fileInfo.Size)
+	}
+	countWork(stat, fileInfo.Size)
 	if fileInfo.IsBlocked {
 		atomic.AddInt64(&s.progress.BlockedFiles, 1)
 	}
@@ -160,23 +755,40 @@ func (s *Scanner) processWork(ctx context.Context, work models.ScanWork) {
 	s.resultChan <- models.ScanWorkResult{FileInfo: fileInfo}
 }
 
-func (s *Scanner) scanDirectory(ctx context.Context, path string, root string) {
+// reportVanished records a file that was deleted after the walk found it.
+// This is not an error; the tree simply changed during the scan.
+func (s *Scanner) reportVanished(file models.FileInfo) {
+	file.Vanished = true
+	atomic.AddInt64(&s.progress.VanishedFiles, 1)
+	s.resultChan <- models.ScanWorkResult{FileInfo: file}
+}
+
+// countWork adds a scanned file to the worker's stats, if collected
+func countWork(stat *models.WorkerStat, size int64) {
+	if stat != nil {
+		stat.Files++
+		stat.Bytes += size
+	}
+}
+
+// scanDirectory emits the entry for dir, then walks it, queueing its files
+// and descending into its subdirectories. chain holds the real paths of the
+// directories above dir when symlinked directories are followed.
+func (s *Scanner) scanDirectory(ctx context.Context, dir models.FileInfo, root string, chain []string) {
 	defer s.dirWg.Done()
 
-	// Voeg alleen de root directory toe aan de resultaten
-	if path == root {
-		dirInfo := models.FileInfo{
-			Path:        path,
-			Name:        filepath.Base(path),
-			IsDirectory: true,
-		}
-		s.resultChan <- models.ScanWorkResult{FileInfo: dirInfo}
-		atomic.AddInt64(&s.progress.TotalFiles, 1)
+	path := dir.Path
+	chain = s.walkChain(chain, path)
+
+	// Subtrees completed by an interrupted scan are not walked again
+	abs, ok := s.enterDir(path, root)
+	if !ok {
+		return
 	}
 
-	entries, err := os.ReadDir(path)
+	entries, err := s.listDir(dir, abs)
 	if err != nil {
-		s.errorChan <- fmt.Errorf("error reading directory %s: %v", path, err)
+		s.checkpoint.release(abs)
 		return
 	}
 
@@ -185,172 +797,422 @@ func (s *Scanner) scanDirectory(ctx context.Context, path string, root string) {
 		case <-ctx.Done():
 			return
 		default:
-			fullPath := filepath.Join(path, entry.Name())
-			info, err := entry.Info()
-			if err != nil {
-				s.errorChan <- fmt.Errorf("error getting info for %s: %v", fullPath, err)
-				continue
-			}
-
-			if info.IsDir() {
-				if s.config.ScanRecursively {
-					// Recursieve modus: we scannen deze directory ook
-					s.dirWg.Add(1)
-					dirInfo := models.FileInfo{
-						Path:        fullPath,
-						Name:        info.Name(),
-						IsDirectory: true,
-					}
-					s.resultChan <- models.ScanWorkResult{FileInfo: dirInfo}
-					atomic.AddInt64(&s.progress.TotalFiles, 1)
-					go s.scanDirectory(ctx, fullPath, root)
-				} else {
-					// Niet-recursieve modus: toon deze directory wel, maar scan niet verder
-					if path == root {
-						dirInfo := models.FileInfo{
-							Path:        fullPath,
-							Name:        info.Name(),
-							IsDirectory: true,
-						}
-						s.resultChan <- models.ScanWorkResult{FileInfo: dirInfo}
-						atomic.AddInt64(&s.progress.TotalFiles, 1)
-					}
-				}
-			} else {
-				if s.config.ExportBlockedToJSON && filepath.Base(fullPath) == "blocked_files.json" {
-					continue
-				}
-				// Bestanden altijd verwerken in de workChan
-				s.workChan <- models.ScanWork{
-					Path:     fullPath,
-					IsDir:    false,
-					Priority: 1,
-				}
-			}
+		}
+		sub, ok := s.visitEntry(ctx, path, abs, entry, root, chain)
+		if !ok {
+			return
+		}
+		if sub != nil {
+			// Recursieve modus: we scannen deze directory ook
+			s.dirWg.Add(1)
+			s.checkpoint.hold(abs)
+			go s.scanDirectory(ctx, *sub, root, chain)
 		}
 	}
+
+	// Every entry is handed out; the directory completes with its last one
+	s.checkpoint.release(abs)
 }
 
-func (s *Scanner) collectResults(result *models.ScanResult, done chan<- struct{}) {
-	defer close(done)
+// enterDir starts tracking the directory at path in the checkpoint and
+// returns its absolute path. It returns false when an interrupted scan
+// already completed the directory, which is then skipped.
+func (s *Scanner) enterDir(path, root string) (string, bool) {
+	abs := s.absPath(path)
+	parent := ""
+	if path != root {
+		parent = filepath.Dir(abs)
+	}
+	if s.checkpoint.isDone(abs) {
+		s.checkpoint.release(parent)
+		s.state.skip(abs)
+		return abs, false
+	}
+	s.checkpoint.enter(abs, parent)
+	return abs, true
+}
 
-	var files []models.FileInfo
-	for res := range s.resultChan {
-		if res.Error != nil {
-			s.mu.Lock()
-			s.progress.Errors = append(s.progress.Errors, res.Error.Error())
-			s.mu.Unlock()
-			continue
-		}
-		files = append(files, res.FileInfo)
-		s.mu.Lock()
-		s.progress.LastUpdated = time.Now()
-		s.progress.CurrentDirectory = filepath.Dir(res.FileInfo.Path)
-		s.mu.Unlock()
+// listDir reads the entries of dir and emits the directory itself, noting
+// why it is not descended into. A directory that could not be read is
+// reported and failed in the checkpoint.
+func (s *Scanner) listDir(dir models.FileInfo, abs string) ([]os.DirEntry, error) {
+	entries, err := s.readDir(dir.Path)
+	if err == nil {
+		// Counted before MaxDirEntries drops the entries below
+		s.recordFanOut(dir.Path, len(entries))
+	}
+
+	// Directories with too many entries are reported but not descended into
+	if err == nil && s.config.MaxDirEntries > 0 && len(entries) > s.config.MaxDirEntries {
+		dir.Note = fmt.Sprintf("skipped: too many entries (%d > %d)", len(entries), s.config.MaxDirEntries)
+		entries = nil
+		s.state.skip(abs)
+	}
+
+	if err != nil {
+		dir.AccessError = err.Error()
 	}
-	result.Files = files
+	// The collector counts the directory once it is accepted
+	s.resultChan <- models.ScanWorkResult{FileInfo: dir}
+
+	if err != nil {
+		s.errorChan <- fmt.Errorf("error reading directory %s: %w", dir.Path, err)
+		// Only directories that were read are recorded as completed
+		s.checkpoint.fail(abs)
+		s.state.skip(abs)
+		return nil, err
+	}
+	return entries, nil
 }
 
-func (s *Scanner) detectFileType(file *models.FileInfo) error {
-	// Open file for type detection
-	f, err := os.Open(file.Path)
+// visitEntry handles one entry of the directory at path: files are handed
+// to the workers and symlinks are reported or resolved. It returns the
+// directory to descend into, if any, and false when the scan was cancelled.
+func (s *Scanner) visitEntry(ctx context.Context, path, abs string, entry os.DirEntry, root string, chain []string) (*models.FileInfo, bool) {
+	fullPath := filepath.Join(path, entry.Name())
+	if s.isSelfPath(fullPath) {
+		return nil, true
+	}
+	info, err := entry.Info()
 	if err != nil {
-		return err
+		s.errorChan <- fmt.Errorf("error getting info for %s: %w", fullPath, err)
+		s.checkpoint.fail(abs)
+		s.state.skip(s.absPath(fullPath))
+		return nil, true
 	}
-	defer f.Close()
 
-	// Read first 512 bytes for MIME type detection
-	buffer := make([]byte, 512)
-	n, err := f.Read(buffer)
-	if err != nil && n == 0 {
-		return err
+	// Entries rejected by the callback are skipped entirely
+	if s.config.ShouldProcess != nil && !s.config.ShouldProcess(fullPath, info) {
+		s.state.skip(s.absPath(fullPath))
+		return nil, true
 	}
+	s.recordDepth(fullPath, root)
 
-	// Detect MIME type
-	file.MimeType = http.DetectContentType(buffer[:n])
+	// Symlinks are reported as they are unless they are followed
+	isSymlink := info.Mode()&os.ModeSymlink != 0
+	if isSymlink {
+		atomic.AddInt64(&s.progress.SymlinkCount, 1)
+		// Dangling links are reported, never followed or treated as errors
+		if broken, note := s.brokenSymlink(fullPath); broken {
+			s.emitSymlink(fullPath, root, info, note, true)
+			return nil, true
+		}
+		target, note := s.resolveSymlink(fullPath, chain)
+		if target == nil {
+			s.emitSymlink(fullPath, root, info, note, false)
+			return nil, true
+		}
+		info = target
+	}
 
-	// Set FileType based on extension and MIME type
-	if file.Extension != "" {
-		file.FileType = strings.TrimPrefix(file.Extension, ".")
-	} else {
-		file.FileType = strings.Split(file.MimeType, "/")[0]
+	if info.IsDir() {
+		dirInfo := models.FileInfo{
+			Path:        fullPath,
+			Name:        info.Name(),
+			IsDirectory: true,
+			EntryType:   models.EntryDir,
+			IsSymlink:   isSymlink,
+		}
+		if s.config.ScanRecursively {
+			return &dirInfo, true
+		}
+		// Niet-recursieve modus: toon deze directory wel, maar scan niet verder
+		s.state.skip(s.absPath(fullPath))
+		if path == root {
+			s.resultChan <- models.ScanWorkResult{FileInfo: dirInfo}
+		}
+		return nil, true
 	}
 
-	return nil
+	if s.config.ExportBlockedToJSON && filepath.Base(fullPath) == "blocked_files.json" {
+		return nil, true
+	}
+	atomic.AddInt64(&s.progress.TotalFiles, 1)
+	atomic.AddInt64(&s.progress.TotalSize, info.Size())
+	s.checkpoint.hold(abs)
+	// Bestanden altijd verwerken in de workChan
+	work := models.ScanWork{
+		Path:     fullPath,
+		Root:     root,
+		IsDir:    false,
+		Priority: 1,
+	}
+	if s.sequential() {
+		s.processWork(ctx, work, nil)
+		return nil, true
+	}
+	select {
+	case s.workChan <- work:
+		return nil, true
+	case <-ctx.Done():
+		return nil, false
+	}
 }
 
-func (s *Scanner) shouldBlockFile(file *models.FileInfo) bool {
-	// Check file size
-	if !s.isFileSizeAllowed(file.Size) {
-		return true
+// collectResults gathers worker results into result.Files, or writes them as
+// NDJSON to stream when one is given
+func (s *Scanner) collectResults(result *models.ScanResult, stream *bufio.Writer, done chan<- struct{}) {
+	defer close(done)
+
+	var encoder *json.Encoder
+	var streamErr error
+	if stream != nil && s.config.StreamCRLF {
+		encoder = json.NewEncoder(crlfWriter{stream})
+	} else if stream != nil {
+		encoder = json.NewEncoder(stream)
 	}
 
-	// Check if file type is allowed
-	if len(s.config.AllowedTypes) > 0 {
-		isAllowed := false
-		for _, allowedType := range s.config.AllowedTypes {
-			if strings.EqualFold(file.Extension, allowedType) {
-				isAllowed = true
-				break
+	var links *hardLinkTracker
+	if s.config.DetectHardLinks {
+		links = newHardLinkTracker()
+		defer links.apply(result)
+	}
+
+	// Progress is only stamped once per ProgressInterval so the collector
+	// does not take mu for every file
+	var lastUpdate time.Time
+
+	// A directory can be reported both as an entry of its parent and as a
+	// root of its own walk; only the first report is kept and counted
+	seenDirs := make(map[string]struct{})
+
+	// collect handles a single result
+	collect := func(res models.ScanWorkResult) {
+		if res.Error != nil {
+			// Onleesbare bestanden zijn niet verdwenen
+			s.state.skip(s.absPath(res.FileInfo.Path))
+			s.recordError(res.Error)
+			return
+		}
+		if res.FileInfo.IsDirectory {
+			if _, ok := seenDirs[res.FileInfo.Path]; ok {
+				return
 			}
+			seenDirs[res.FileInfo.Path] = struct{}{}
+			atomic.AddInt64(&s.progress.TotalFiles, 1)
+		}
+		s.state.observe(s.absPath(res.FileInfo.Path), &res.FileInfo)
+		s.quarantine(&res.FileInfo)
+		if s.relBase != "" {
+			s.relativize(&res.FileInfo)
 		}
-		if !isAllowed {
-			return true
+		if links != nil {
+			links.add(res.FileInfo)
+		}
+		if category := res.FileInfo.Category; category != "" {
+			if result.CategoryStats == nil {
+				result.CategoryStats = make(map[string]int64)
+			}
+			result.CategoryStats[category]++
+		}
+		if file := res.FileInfo; file.IsBlocked {
+			// Ook weggelaten en gestreamde bestanden tellen mee
+			atomic.AddInt64(&s.progress.BlockedSize, file.Size)
+			if result.BlockedByReason == nil {
+				result.BlockedByReason = make(map[string][]string)
+			}
+			group := models.ReasonGroup(file.BlockReason)
+			result.BlockedByReason[group] = append(result.BlockedByReason[group], file.Path)
+		}
+		if file := res.FileInfo; file.EntryType == models.EntryFile {
+			if result.AgeBuckets == nil {
+				result.AgeBuckets = make(map[string]int64)
+			}
+			result.AgeBuckets[models.AgeBucket(file.ModTime, s.progress.StartTime)]++
+		}
+		if s.config.OnFile != nil {
+			s.config.OnFile(res.FileInfo)
+		}
+		s.incremental.add(res.FileInfo)
+		if s.config.OmitBlocked && res.FileInfo.IsBlocked {
+			// Alleen bewaren voor de export
+			if s.config.ExportBlockedToJSON {
+				s.omittedBlocked = append(s.omittedBlocked, res.FileInfo)
+			}
+		} else if encoder != nil {
+			// Alleen de eerste schrijffout rapporteren
+			if err := encoder.Encode(res.FileInfo); err != nil && streamErr == nil {
+				streamErr = err
+				s.recordError(fmt.Errorf("Failed to write stream file: %v", err))
+			}
+			// Alleen geblokkeerde bestanden blijven in het geheugen, voor de export
+			if s.config.ExportBlockedToJSON && res.FileInfo.IsBlocked {
+				s.omittedBlocked = append(s.omittedBlocked, res.FileInfo)
+			}
+		} else if err := s.sink.Add(res.FileInfo); err != nil && s.sinkErr == nil {
+			// Alleen de eerste fout van de sink rapporteren
+			s.sinkErr = err
+			s.recordError(fmt.Errorf("Failed to add result to sink: %v", err))
+		}
+		if now := time.Now(); now.Sub(lastUpdate) >= s.config.ProgressInterval {
+			lastUpdate = now
+			s.updateProgress(now, filepath.Dir(res.FileInfo.Path))
 		}
 	}
 
-	// Check blocked patterns
-	for _, pattern := range s.config.BlockedPatterns {
-		matched, err := filepath.Match(pattern, file.Name)
-		if err == nil && matched {
-			return true
+	for res := range s.resultChan {
+		s.collectMu.Lock()
+		if !s.abandoned {
+			collect(res)
 		}
+		s.collectMu.Unlock()
 	}
 
-	return false
+	// An abandoned scan has returned; nothing may be reported anymore
+	s.collectMu.Lock()
+	abandoned := s.abandoned
+	s.collectMu.Unlock()
+	if abandoned {
+		return
+	}
+
+	if !lastUpdate.IsZero() {
+		s.updateProgress(time.Now(), s.progress.CurrentDirectory)
+	}
+
+	for _, paths := range result.BlockedByReason {
+		sort.Strings(paths)
+	}
+
+	s.mu.Lock()
+	if s.config.SortResults {
+		sortByPath(s.files)
+		sortByPath(s.omittedBlocked)
+	}
+	result.Files = s.files
+	s.mu.Unlock()
 }
 
-func (s *Scanner) getBlockReason(file *models.FileInfo) string {
-	if !s.isFileSizeAllowed(file.Size) {
-		return "File size exceeds limit"
+// updateProgress stamps the progress and reports it to OnProgress
+func (s *Scanner) updateProgress(now time.Time, dir string) {
+	s.mu.Lock()
+	s.progress.LastUpdated = now
+	s.progress.CurrentDirectory = dir
+	s.mu.Unlock()
+
+	if s.config.OnProgress != nil {
+		s.config.OnProgress(*s.GetProgress())
 	}
+}
 
-	if len(s.config.AllowedTypes) > 0 {
-		isAllowed := false
-		for _, allowedType := range s.config.AllowedTypes {
-			if strings.EqualFold(file.Extension, allowedType) {
-				isAllowed = true
-				break
-			}
+// sortByPath orders files by path so repeated scans produce identical output
+func sortByPath(files []models.FileInfo) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+}
+
+func (s *Scanner) detectFileType(file *models.FileInfo) error {
+	// Trusted extensions name their type without reading the content
+	trusted := s.trustedTypes[file.Extension]
+	if trusted {
+		file.MimeType = mimeForExtension(file.Extension)
+		file.FileType = s.fileTypeFor(file.Name, file.MimeType)
+		file.Category = s.categoryFor(file.Extension, file.MimeType)
+		if !s.config.ComputeHash && !s.config.QuickHash && !s.searchesKeywords(file) {
+			return nil
 		}
-		if !isAllowed {
-			return "File type not allowed"
+	}
+
+	// Reads are serialized through the I/O gate when configured
+	s.acquireIO()
+	defer s.releaseIO()
+
+	// Open file for type detection
+	f, err := s.openFile(file.Path)
+	if err != nil {
+		return err
+	}
+	defer s.closeFile(f)
+
+	if !trusted {
+		if err := s.sniffContent(f, file); err != nil {
+			return err
+		}
+	}
+
+	if s.config.QuickHash {
+		if file.QuickHash, err = quickHashContent(f, file.Size, s.newHash); err != nil {
+			return err
+		}
+	}
+
+	if s.config.ComputeHash {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if file.Hash, err = hashContent(f, s.newHash); err != nil {
+			return err
 		}
 	}
 
-	for _, pattern := range s.config.BlockedPatterns {
-		matched, _ := filepath.Match(pattern, file.Name)
-		if matched {
-			return fmt.Sprintf("File matches blocked pattern: %s", pattern)
+	if s.searchesKeywords(file) {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if file.MatchedKeywords, err = findKeywords(f, s.config.Keywords); err != nil {
+			return err
 		}
 	}
 
-	return "Unknown reason"
+	return nil
 }
 
-func (s *Scanner) isFileSizeAllowed(size int64) bool {
-	return size <= int64(s.config.MaxFileSizeMB)*1024*1024
+// sniffContent detects the MIME type of file from the opening bytes of r and
+// runs the checks that need the content
+func (s *Scanner) sniffContent(r io.Reader, file *models.FileInfo) error {
+	// Read first 512 bytes for MIME type detection, or more when a longer
+	// preview is wanted
+	buffer := make([]byte, max(sniffLen, s.config.PreviewBytes))
+	n, err := r.Read(buffer)
+	// Empty files report io.EOF straight away; they are not an access error
+	if err != nil && err != io.EOF && n == 0 {
+		return err
+	}
+	head := buffer[:min(n, sniffLen)]
+
+	// Detect MIME type
+	file.MimeType = http.DetectContentType(head)
+
+	if s.config.ComputeEntropy {
+		file.Entropy = shannonEntropy(head)
+	}
+
+	if s.config.FlagExtensionMismatch {
+		file.ExtensionMismatch = extensionMismatch(file.Extension, file.MimeType, head)
+	}
+
+	if s.config.PreviewBytes > 0 && s.isText(file) {
+		file.Preview = textPreview(buffer[:min(n, s.config.PreviewBytes)])
+	}
+
+	// Set FileType based on extension and MIME type
+	file.FileType = s.contentFileType(file, head)
+	file.Category = s.categoryFor(file.Extension, file.MimeType)
+	return nil
 }
 
 func (s *Scanner) startScan(root string) error {
-	info, err := os.Stat(root)
+	if s.explicitPaths {
+		atomic.AddInt64(&s.progress.TotalFiles, 1)
+		s.workChan <- pathWork(root)
+		return nil
+	}
+
+	info, err := s.statRoot(root)
 	if err != nil {
 		return err
 	}
 
+	// Directories are tracked by dirWg until their walk completes
+	if info.IsDir() {
+		s.dirWg.Add(1)
+	}
+
 	// Queue the root directory
 	s.workChan <- models.ScanWork{
 		Path:     root,
+		Root:     root,
 		IsDir:    info.IsDir(),
 		Priority: 1,
 	}
@@ -358,6 +1220,12 @@ func (s *Scanner) startScan(root string) error {
 	return nil
 }
 
+// pathWork builds the work item for a path passed to ScanPaths. Its parent
+// directory serves as root for the allowlist.
+func pathWork(path string) models.ScanWork {
+	return models.ScanWork{Path: path, Root: filepath.Dir(path), IsDir: false, Priority: 1}
+}
+
 // GetProgress returns a copy of the current progress
 func (s *Scanner) GetProgress() *models.ScanProgress {
 	s.mu.Lock()
@@ -365,15 +1233,31 @@ func (s *Scanner) GetProgress() *models.ScanProgress {
 
 	// Create a deep copy of progress
 	progress := &models.ScanProgress{
-		TotalFiles:       s.progress.TotalFiles,
-		ScannedFiles:     s.progress.ScannedFiles,
-		TotalSize:        s.progress.TotalSize,
-		ScannedSize:      s.progress.ScannedSize,
-		BlockedFiles:     s.progress.BlockedFiles,
+		TotalFiles:       atomic.LoadInt64(&s.progress.TotalFiles),
+		ScannedFiles:     atomic.LoadInt64(&s.progress.ScannedFiles),
+		TotalSize:        atomic.LoadInt64(&s.progress.TotalSize),
+		ScannedSize:      atomic.LoadInt64(&s.progress.ScannedSize),
+		BlockedFiles:     atomic.LoadInt64(&s.progress.BlockedFiles),
+		BlockedSize:      atomic.LoadInt64(&s.progress.BlockedSize),
+		SymlinkCount:     atomic.LoadInt64(&s.progress.SymlinkCount),
+		BrokenSymlinks:   atomic.LoadInt64(&s.progress.BrokenSymlinks),
+		PermissionDenied: atomic.LoadInt64(&s.progress.PermissionDenied),
+		DeviceCount:      atomic.LoadInt64(&s.progress.DeviceCount),
+		SocketCount:      atomic.LoadInt64(&s.progress.SocketCount),
+		PipeCount:        atomic.LoadInt64(&s.progress.PipeCount),
+		VanishedFiles:    atomic.LoadInt64(&s.progress.VanishedFiles),
+		EmptyFiles:       atomic.LoadInt64(&s.progress.EmptyFiles),
+		BytesRead:        atomic.LoadInt64(&s.progress.BytesRead),
 		StartTime:        s.progress.StartTime,
 		LastUpdated:      s.progress.LastUpdated,
 		CurrentDirectory: s.progress.CurrentDirectory,
+		Paused:           s.IsPaused(),
+		WorkQueueDepth:   len(s.workChan),
+		ResultQueueDepth: len(s.resultChan),
 	}
+	progress.ReadBudgetExhausted = s.progress.ReadBudgetExhausted
+	progress.ModifiedDuringScan = atomic.LoadInt64(&s.progress.ModifiedDuringScan)
+	progress.SampledOut = atomic.LoadInt64(&s.progress.SampledOut)
 
 	// Copy errors slice
 	if len(s.progress.Errors) > 0 {
@@ -383,3 +1267,22 @@ func (s *Scanner) GetProgress() *models.ScanProgress {
 
 	return progress
 }
+
+// GetPartialResult returns a snapshot of the files collected so far together
+// with the current progress. It is safe to call while a scan is running.
+func (s *Scanner) GetPartialResult() *models.ScanResult {
+	progress := s.GetProgress()
+
+	s.mu.Lock()
+	files := make([]models.FileInfo, len(s.files))
+	copy(files, s.files)
+	s.mu.Unlock()
+
+	duration := time.Since(progress.StartTime)
+	return &models.ScanResult{
+		Files:         files,
+		Progress:      *progress,
+		Duration:      duration,
+		DurationHuman: duration.String(),
+	}
+}
@@ -1,10 +1,18 @@
 package scanner
 
 import (
+	"encoding/json"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"filesystem-logger/internal/models"
 )
@@ -96,6 +104,11 @@ func TestScanner(t *testing.T) {
 				t.Errorf("Expected %d blocked files, got %d",
 					tt.expectedBlocks, blockedCount)
 			}
+
+			if result.DurationHuman != result.Duration.String() {
+				t.Errorf("Expected DurationHuman %s, got %s",
+					result.Duration, result.DurationHuman)
+			}
 		})
 	}
 }
@@ -260,7 +273,7 @@ func TestInvalidPaths(t *testing.T) {
 			path:    "/path/that/does/not/exist",
 			wantErr: true,
 			errCheck: func(err error) bool {
-				return os.IsNotExist(err)
+				return errors.Is(err, ErrRootNotFound) && errors.Is(err, fs.ErrNotExist)
 			},
 		},
 		{
@@ -344,3 +357,958 @@ func TestPermissions(t *testing.T) {
 		t.Error("No-read directory not found in scan results")
 	}
 }
+
+// TestCustomBlockRule test een eigen regel die blokkeert op MIME type
+func TestCustomBlockRule(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string][]byte{
+		"notes.txt": []byte("plain text"),
+		"photo.jpg": {0xFF, 0xD8, 0xFF, 0xE0},
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+	scanner.AddRule(models.BlockRuleFunc(func(file *models.FileInfo) (bool, string) {
+		if strings.HasPrefix(file.MimeType, "image/") {
+			return true, "Images are not allowed"
+		}
+		return false, ""
+	}))
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		switch file.Name {
+		case "photo.jpg":
+			if !file.IsBlocked || file.BlockReason != "Images are not allowed" {
+				t.Errorf("Expected photo.jpg to be blocked by custom rule, got blocked=%v reason=%q",
+					file.IsBlocked, file.BlockReason)
+			}
+		case "notes.txt":
+			if file.IsBlocked {
+				t.Errorf("Expected notes.txt to be allowed, got reason %q", file.BlockReason)
+			}
+		}
+	}
+}
+
+// TestScanGlob test het scannen van meerdere directories via een glob pattern
+func TestScanGlob(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := []string{
+		"alpha/logs/a.log",
+		"beta/logs/b.log",
+		"gamma/other/c.log",
+	}
+	for _, name := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte("log line"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, ScanRecursively: true})
+	result, err := scanner.ScanGlob(filepath.Join(tempDir, "*", "logs"))
+	if err != nil {
+		t.Fatalf("ScanGlob failed: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, file := range result.Files {
+		found[file.Name] = true
+	}
+	for _, name := range []string{"a.log", "b.log"} {
+		if !found[name] {
+			t.Errorf("Expected %s in glob scan results", name)
+		}
+	}
+	if found["c.log"] {
+		t.Error("Expected c.log to be excluded from glob scan results")
+	}
+
+	t.Run("No matches", func(t *testing.T) {
+		_, err := New(models.ScanConfig{}).ScanGlob(filepath.Join(tempDir, "*", "missing"))
+		if err == nil {
+			t.Error("Expected an error for a pattern without matches")
+		}
+	})
+}
+
+// TestStreamToFile test dat resultaten als NDJSON naar schijf worden geschreven
+func TestStreamToFile(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 5)
+
+	streamPath := filepath.Join(t.TempDir(), "results.ndjson")
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, StreamToFile: streamPath})
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(result.Files) != 0 {
+		t.Errorf("Expected no files in memory, got %d", len(result.Files))
+	}
+	if result.Progress.ScannedFiles != 5 {
+		t.Errorf("Expected 5 scanned files in summary, got %d", result.Progress.ScannedFiles)
+	}
+
+	f, err := os.Open(streamPath)
+	if err != nil {
+		t.Fatalf("Failed to open stream file: %v", err)
+	}
+	defer f.Close()
+
+	paths := make(map[string]bool)
+	decoder := json.NewDecoder(f)
+	for decoder.More() {
+		var file models.FileInfo
+		if err := decoder.Decode(&file); err != nil {
+			t.Fatalf("Failed to decode stream entry: %v", err)
+		}
+		paths[file.Path] = true
+	}
+
+	// root dir + 5 files
+	if len(paths) != 6 {
+		t.Errorf("Expected 6 entries in stream file, got %d", len(paths))
+	}
+	if !paths[tempDir] {
+		t.Error("Expected root directory in stream file")
+	}
+}
+
+func TestStreamToFileExport(t *testing.T) {
+	tempDir := t.TempDir()
+	for name, size := range map[string]int{"small.txt": 100, "large.txt": 2 * 1024 * 1024} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:       1,
+		ExportBlockedToJSON: true,
+		StreamToFile:        filepath.Join(t.TempDir(), "results.ndjson"),
+	})
+	if _, err := scanner.Scan(tempDir); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "blocked_files.json"))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	var exported struct {
+		BlockedCount int64             `json:"blockedCount"`
+		BlockedFiles []models.FileInfo `json:"blockedFiles"`
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to parse export: %v", err)
+	}
+	if exported.BlockedCount != 1 || len(exported.BlockedFiles) != 1 || exported.BlockedFiles[0].Name != "large.txt" {
+		t.Errorf("Expected the streamed blocked file in the export, got %s", data)
+	}
+}
+
+func TestStreamCRLF(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 3)
+
+	for _, crlf := range []bool{false, true} {
+		streamPath := filepath.Join(t.TempDir(), "results.ndjson")
+		scanner := New(models.ScanConfig{MaxFileSizeMB: 10, StreamToFile: streamPath, StreamCRLF: crlf})
+		if _, err := scanner.Scan(tempDir); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+
+		data, err := os.ReadFile(streamPath)
+		if err != nil {
+			t.Fatalf("Failed to read stream file: %v", err)
+		}
+		lines := strings.Count(string(data), "\n")
+		crlfLines := strings.Count(string(data), "\r\n")
+		if lines != 4 {
+			t.Errorf("Expected 4 lines, got %d", lines)
+		}
+		if crlf && crlfLines != lines || !crlf && crlfLines != 0 {
+			t.Errorf("Expected CRLF=%v, got %d CRLF line ends of %d", crlf, crlfLines, lines)
+		}
+	}
+}
+
+// TestSelfGeneratedFiles test dat eigen uitvoer in de gescande map wordt overgeslagen
+func TestSelfGeneratedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 3)
+
+	streamPath := filepath.Join(tempDir, "results.ndjson")
+	config := models.ScanConfig{
+		MaxFileSizeMB:       10,
+		BlockedPatterns:     []string{"file0*"},
+		ExportBlockedToJSON: true,
+		StreamToFile:        streamPath,
+	}
+
+	// The second scan finds the output of the first one on disk
+	for i := 0; i < 2; i++ {
+		result, err := New(config).Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if result.Progress.TotalFiles != 4 { // root dir + 3 files
+			t.Errorf("Scan %d: expected 4 entries, got %d", i+1, result.Progress.TotalFiles)
+		}
+	}
+
+	data, err := os.ReadFile(streamPath)
+	if err != nil {
+		t.Fatalf("Failed to read stream file: %v", err)
+	}
+	for _, name := range []string{"results.ndjson", "blocked_files.json"} {
+		if strings.Contains(string(data), name) {
+			t.Errorf("Expected %s not to appear in the results", name)
+		}
+	}
+}
+
+// TestQueueDepth test de bezetting van de work- en result-queues tijdens een scan
+func TestQueueDepth(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 50)
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:    10,
+		WorkerCount:      1,
+		WorkBufferSize:   64,
+		ResultBufferSize: 8,
+	})
+	if cap(scanner.workChan) != 64 || cap(scanner.resultChan) != 8 {
+		t.Fatalf("Expected independent buffer sizes 64/8, got %d/%d",
+			cap(scanner.workChan), cap(scanner.resultChan))
+	}
+
+	// Vertraag het openen zodat de work queue zich vult
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" {
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	}}
+
+	done := make(chan struct{})
+	var maxDepth int
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			progress := scanner.GetProgress()
+			if progress.WorkQueueDepth > maxDepth {
+				maxDepth = progress.WorkQueueDepth
+			}
+			if progress.WorkQueueDepth > 64 || progress.ResultQueueDepth > 8 {
+				t.Errorf("Queue depth exceeds capacity: %d/%d",
+					progress.WorkQueueDepth, progress.ResultQueueDepth)
+			}
+			time.Sleep(500 * time.Microsecond)
+		}
+	}()
+
+	if _, err := scanner.Scan(tempDir); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	<-done
+
+	if maxDepth == 0 {
+		t.Error("Expected a non-zero work queue depth during the scan")
+	}
+}
+
+// TestOmitBlocked test dat geblokkeerde bestanden niet in de resultaten komen
+func TestOmitBlocked(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]int64{
+		"small.txt": 100,
+		"large.txt": 2 * 1024 * 1024,
+		"huge.txt":  3 * 1024 * 1024,
+	}
+	for name, size := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:       1,
+		OmitBlocked:         true,
+		ExportBlockedToJSON: true,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	for _, file := range result.Files {
+		if file.IsBlocked {
+			t.Errorf("Expected blocked file %s to be omitted", file.Name)
+		}
+	}
+	if len(result.Files) != 2 { // root dir + small.txt
+		t.Errorf("Expected 2 entries, got %d", len(result.Files))
+	}
+	if result.Progress.BlockedFiles != 2 {
+		t.Errorf("Expected BlockedFiles=2, got %d", result.Progress.BlockedFiles)
+	}
+	if expected := testFiles["large.txt"] + testFiles["huge.txt"]; result.Progress.BlockedSize != expected {
+		t.Errorf("Expected BlockedSize=%d for the omitted files, got %d", expected, result.Progress.BlockedSize)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "blocked_files.json"))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	var exported struct {
+		BlockedCount int64 `json:"blockedCount"`
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to parse export: %v", err)
+	}
+	if exported.BlockedCount != 2 {
+		t.Errorf("Expected omitted files in export, got blockedCount=%d", exported.BlockedCount)
+	}
+}
+
+func TestOmitBlockedStream(t *testing.T) {
+	tempDir := t.TempDir()
+	for name, size := range map[string]int{"small.txt": 100, "large.txt": 2 * 1024 * 1024} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	streamPath := filepath.Join(t.TempDir(), "results.ndjson")
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:       1,
+		OmitBlocked:         true,
+		ExportBlockedToJSON: true,
+		StreamToFile:        streamPath,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if result.Progress.BlockedFiles != 1 {
+		t.Errorf("Expected BlockedFiles=1, got %d", result.Progress.BlockedFiles)
+	}
+
+	data, err := os.ReadFile(streamPath)
+	if err != nil {
+		t.Fatalf("Failed to read stream file: %v", err)
+	}
+	if strings.Contains(string(data), "large.txt") {
+		t.Errorf("Expected the blocked file to be left out of the stream, got:\n%s", data)
+	}
+
+	data, err = os.ReadFile(filepath.Join(tempDir, "blocked_files.json"))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if !strings.Contains(string(data), "large.txt") {
+		t.Errorf("Expected the omitted file in the export, got %s", data)
+	}
+}
+
+// TestGetPartialResult test dat het tussenresultaat groeit tijdens de scan
+func TestGetPartialResult(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 20)
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, WorkerCount: 1})
+	scanner.fs = &hookFS{before: func(op, name string) error {
+		if op == "open" {
+			time.Sleep(5 * time.Millisecond)
+		}
+		return nil
+	}}
+
+	done := make(chan struct{})
+	var sizes []int
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			sizes = append(sizes, len(scanner.GetPartialResult().Files))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	<-done
+
+	grew := false
+	for i := 1; i < len(sizes); i++ {
+		if sizes[i] < sizes[i-1] {
+			t.Fatalf("Partial result shrank: %v", sizes)
+		}
+		if sizes[i] > sizes[i-1] && sizes[i-1] > 0 && sizes[i] < len(result.Files) {
+			grew = true
+		}
+	}
+	if !grew {
+		t.Errorf("Expected the partial result to grow during the scan, got sizes %v", sizes)
+	}
+}
+
+// TestScanSingleFile test het scannen van een enkel bestand als root
+func TestScanSingleFile(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "oversized.bin")
+	if err := os.WriteFile(path, make([]byte, 2*1024*1024), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 1, ScanRecursively: true})
+	result, err := scanner.Scan(path)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(result.Files) != 1 {
+		t.Fatalf("Expected exactly 1 file, got %d", len(result.Files))
+	}
+	file := result.Files[0]
+	if file.Path != path || file.IsDirectory {
+		t.Errorf("Expected regular file %s, got %+v", path, file)
+	}
+	if !file.IsBlocked || !strings.HasPrefix(file.BlockReason, "File size exceeds limit") {
+		t.Errorf("Expected file to be blocked by size, got blocked=%v reason=%q", file.IsBlocked, file.BlockReason)
+	}
+	if file.FileType != "bin" {
+		t.Errorf("Expected file type bin, got %q", file.FileType)
+	}
+	if result.Progress.ScannedFiles != 1 || result.Progress.BlockedFiles != 1 {
+		t.Errorf("Expected 1 scanned and 1 blocked file, got %d and %d",
+			result.Progress.ScannedFiles, result.Progress.BlockedFiles)
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 30)
+	for _, sub := range []string{"a", "b"} {
+		dir := filepath.Join(tempDir, sub)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		createFiles(t, dir, 10)
+	}
+
+	scanPaths := func() []string {
+		scanner := New(models.ScanConfig{
+			MaxFileSizeMB:   10,
+			ScanRecursively: true,
+			WorkerCount:     8,
+			SortResults:     true,
+		})
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+
+		paths := make([]string, len(result.Files))
+		for i, file := range result.Files {
+			paths[i] = file.Path
+		}
+		return paths
+	}
+
+	first := scanPaths()
+	second := scanPaths()
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected identical ordering across scans:\n%v\n%v", first, second)
+	}
+	if !sort.StringsAreSorted(first) {
+		t.Errorf("Expected files ordered by path, got %v", first)
+	}
+}
+
+func TestShouldProcess(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := []string{"a.txt", "b.log", "sub/c.txt", "sub/d.bin"}
+	for _, name := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	var mu sync.Mutex
+	var consulted []string
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		ScanRecursively: true,
+		ShouldProcess: func(path string, info fs.FileInfo) bool {
+			mu.Lock()
+			consulted = append(consulted, path)
+			mu.Unlock()
+			return info.IsDir() || filepath.Ext(path) == ".txt"
+		},
+	})
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	var scanned []string
+	for _, file := range result.Files {
+		if !file.IsDirectory {
+			scanned = append(scanned, file.Name)
+		}
+	}
+	sort.Strings(scanned)
+
+	if expected := []string{"a.txt", "c.txt"}; !reflect.DeepEqual(scanned, expected) {
+		t.Errorf("Expected only %v to be scanned, got %v", expected, scanned)
+	}
+	if result.Progress.TotalFiles != 4 { // root + sub + 2 files
+		t.Errorf("Expected skipped files not to be counted, got %d total", result.Progress.TotalFiles)
+	}
+	if len(consulted) != 5 {
+		t.Errorf("Expected the callback for every entry below the root, got %v", consulted)
+	}
+}
+
+func TestFailOnError(t *testing.T) {
+	tempDir := t.TempDir()
+	locked := filepath.Join(tempDir, "locked")
+	if err := os.Mkdir(locked, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	createFiles(t, tempDir, 3)
+
+	// Simuleer een onleesbare directory, ook wanneer de tests als root draaien
+	unreadable := &hookFS{before: func(op, name string) error {
+		if op == "readdir" && name == locked {
+			return &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+		}
+		return nil
+	}}
+
+	tests := []struct {
+		name        string
+		failOnError bool
+	}{
+		{name: "Errors reported in result only", failOnError: false},
+		{name: "Errors fail the scan", failOnError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:   10,
+				ScanRecursively: true,
+				FailOnError:     tt.failOnError,
+			})
+			scanner.fs = unreadable
+
+			result, err := scanner.Scan(tempDir)
+			if result == nil {
+				t.Fatalf("Expected a populated result, got error %v", err)
+			}
+			if (err != nil) != tt.failOnError {
+				t.Errorf("Expected error=%v, got %v", tt.failOnError, err)
+			}
+			if err != nil && !errors.Is(err, fs.ErrPermission) {
+				t.Errorf("Expected the error to wrap the permission error, got %v", err)
+			}
+			if result.Success || result.Progress.PermissionDenied != 1 {
+				t.Errorf("Expected one permission error, got %d", result.Progress.PermissionDenied)
+			}
+			if result.Progress.ScannedFiles != 3 {
+				t.Errorf("Expected the readable files to be scanned, got %d", result.Progress.ScannedFiles)
+			}
+		})
+	}
+}
+
+func TestCollectWorkerStats(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 40)
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, WorkerCount: 4, CollectWorkerStats: true})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(result.PerWorkerStats) != 4 {
+		t.Fatalf("Expected stats for 4 workers, got %d", len(result.PerWorkerStats))
+	}
+
+	var files, bytes int64
+	for i, stat := range result.PerWorkerStats {
+		if stat.Worker != i {
+			t.Errorf("Expected stats ordered by worker index, got %d at %d", stat.Worker, i)
+		}
+		files += stat.Files
+		bytes += stat.Bytes
+	}
+	if files != result.Progress.ScannedFiles {
+		t.Errorf("Expected per-worker files to sum to %d, got %d", result.Progress.ScannedFiles, files)
+	}
+	if bytes != result.Progress.ScannedSize {
+		t.Errorf("Expected per-worker bytes to sum to %d, got %d", result.Progress.ScannedSize, bytes)
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		result, err := New(models.ScanConfig{MaxFileSizeMB: 10}).Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if result.PerWorkerStats != nil {
+			t.Errorf("Expected no worker stats, got %v", result.PerWorkerStats)
+		}
+	})
+}
+
+func TestOnFile(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 10)
+
+	// Geen lock nodig: de callback draait alleen op de collector goroutine
+	var seen []models.FileInfo
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		BlockedPatterns: []string{"file00*"},
+		WorkerCount:     4,
+		OnFile: func(file models.FileInfo) {
+			seen = append(seen, file)
+		},
+	})
+
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(seen, result.Files) {
+		t.Errorf("Expected the callback to see the result files in order:\n%v\n%v", seen, result.Files)
+	}
+
+	blocked := 0
+	for _, file := range seen {
+		if file.IsBlocked {
+			blocked++
+		}
+	}
+	if blocked == 0 {
+		t.Error("Expected blocked files to be passed to the callback")
+	}
+}
+
+func TestProgressInterval(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 20)
+
+	const interval = 30 * time.Millisecond
+
+	// Geen lock nodig: beide callbacks draaien op de collector goroutine
+	var stamps []time.Time
+	var scanner *Scanner
+	progressCalls := 0
+	scanner = New(models.ScanConfig{
+		MaxFileSizeMB:    10,
+		ProgressInterval: interval,
+		OnFile: func(file models.FileInfo) {
+			time.Sleep(5 * time.Millisecond)
+			stamp := scanner.GetProgress().LastUpdated
+			if len(stamps) == 0 || !stamp.Equal(stamps[len(stamps)-1]) {
+				stamps = append(stamps, stamp)
+			}
+		},
+		OnProgress: func(progress models.ScanProgress) {
+			progressCalls++
+		},
+	})
+
+	if _, err := scanner.Scan(tempDir); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	// De eerste waarde is de nulwaarde van voor de eerste update
+	for i := 2; i < len(stamps); i++ {
+		if gap := stamps[i].Sub(stamps[i-1]); gap < interval {
+			t.Errorf("Expected LastUpdated to change at most every %v, changed after %v", interval, gap)
+		}
+	}
+	if len(stamps) < 3 {
+		t.Errorf("Expected LastUpdated to change during the scan, saw %d values", len(stamps))
+	}
+	if progressCalls == 0 || progressCalls > len(stamps)+1 {
+		t.Errorf("Expected OnProgress once per update, got %d calls for %d updates", progressCalls, len(stamps)-1)
+	}
+}
+
+func TestVanishedFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		op   string
+	}{
+		{name: "Removed before stat", op: "stat"},
+		{name: "Removed before open", op: "open"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			createFiles(t, tempDir, 3)
+			victim := filepath.Join(tempDir, "file01.txt")
+
+			// Verwijder het bestand tussen ontdekking en verwerking
+			scanner := New(models.ScanConfig{MaxFileSizeMB: 10, FailOnError: true})
+			scanner.fs = &hookFS{before: func(op, name string) error {
+				if op == tt.op && name == victim {
+					os.Remove(name)
+				}
+				return nil
+			}}
+
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Expected a vanished file not to fail the scan, got %v", err)
+			}
+			if !result.Success || len(result.Progress.Errors) > 0 {
+				t.Errorf("Expected a successful scan, got errors %v", result.Progress.Errors)
+			}
+			if result.Progress.VanishedFiles != 1 {
+				t.Errorf("Expected 1 vanished file, got %d", result.Progress.VanishedFiles)
+			}
+
+			for _, file := range result.Files {
+				if file.Path == victim {
+					if !file.Vanished || file.AccessError != "" {
+						t.Errorf("Expected %s to be marked vanished without access error, got %+v", file.Name, file)
+					}
+				} else if file.Vanished {
+					t.Errorf("Expected %s not to be marked vanished", file.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestMaxDirEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	crowded := filepath.Join(tempDir, "cache")
+	small := filepath.Join(tempDir, "docs")
+	for _, dir := range []string{crowded, small} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	createFiles(t, crowded, 20)
+	createFiles(t, small, 3)
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, ScanRecursively: true, MaxDirEntries: 10})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	children := map[string]int{}
+	for _, file := range result.Files {
+		children[filepath.Dir(file.Path)]++
+		if file.Path == crowded {
+			if !strings.HasPrefix(file.Note, "skipped: too many entries") {
+				t.Errorf("Expected crowded directory to be marked as skipped, got note %q", file.Note)
+			}
+		} else if file.Note != "" {
+			t.Errorf("Expected no note for %s, got %q", file.Path, file.Note)
+		}
+	}
+
+	if children[crowded] != 0 {
+		t.Errorf("Expected the children of the crowded directory to be skipped, got %d", children[crowded])
+	}
+	if children[small] != 3 {
+		t.Errorf("Expected 3 files in the small directory, got %d", children[small])
+	}
+	if result.Progress.ScannedFiles != 3 {
+		t.Errorf("Expected 3 scanned files, got %d", result.Progress.ScannedFiles)
+	}
+}
+
+func TestEmptyFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string][]byte{
+		"empty.txt": nil,
+		"data.txt":  []byte("content"),
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tempDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		block bool
+	}{
+		{name: "Flag only", block: false},
+		{name: "Flag and block", block: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{MaxFileSizeMB: 10, BlockEmptyFiles: tt.block, ComputeHash: true})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			if result.Progress.EmptyFiles != 1 {
+				t.Errorf("Expected 1 empty file, got %d", result.Progress.EmptyFiles)
+			}
+
+			for _, file := range result.Files {
+				switch file.Name {
+				case "empty.txt":
+					if !file.IsEmpty {
+						t.Error("Expected empty.txt to be flagged as empty")
+					}
+					if file.AccessError != "" {
+						t.Errorf("Expected no access error for an empty file, got %q", file.AccessError)
+					}
+					if file.Hash == "" {
+						t.Error("Expected empty files to be hashed")
+					}
+					if file.IsBlocked != tt.block {
+						t.Errorf("Expected blocked=%v, got %v (%s)", tt.block, file.IsBlocked, file.BlockReason)
+					}
+				case "data.txt":
+					if file.IsEmpty || file.IsBlocked {
+						t.Errorf("Expected data.txt to be neither empty nor blocked, got %+v", file)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestScanPaths(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string][]byte{
+		"notes.txt":     []byte("plain text"),
+		"setup.exe":     []byte("MZ"),
+		"sub/other.txt": []byte("not listed"),
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	paths := []string{
+		filepath.Join(tempDir, "notes.txt"),
+		filepath.Join(tempDir, "setup.exe"),
+		filepath.Join(tempDir, "missing.txt"),
+		filepath.Join(tempDir, "sub"),
+	}
+
+	for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+		t.Run(mode, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:   10,
+				ScanRecursively: true,
+				BlockedPatterns: []string{"*.exe"},
+				TraversalMode:   mode,
+			})
+			result, err := scanner.ScanPaths(paths)
+			if err != nil {
+				t.Fatalf("ScanPaths failed: %v", err)
+			}
+
+			files := make(map[string]models.FileInfo)
+			for _, file := range result.Files {
+				files[file.Name] = file
+			}
+			if len(files) != len(paths) {
+				t.Errorf("Expected exactly the %d given paths, got %d entries", len(paths), len(result.Files))
+			}
+
+			if file := files["notes.txt"]; file.IsBlocked || file.MimeType == "" {
+				t.Errorf("Expected notes.txt to be detected and allowed, got %+v", file)
+			}
+			if file := files["setup.exe"]; !file.IsBlocked {
+				t.Error("Expected setup.exe to be blocked")
+			}
+			if file := files["missing.txt"]; !file.Vanished {
+				t.Errorf("Expected missing.txt to be reported as vanished, got %+v", file)
+			}
+			if file := files["sub"]; !file.IsDirectory {
+				t.Errorf("Expected sub to be listed as a directory, got %+v", file)
+			}
+			if _, walked := files["other.txt"]; walked {
+				t.Error("Expected directories not to be walked")
+			}
+		})
+	}
+
+	t.Run("No paths", func(t *testing.T) {
+		if _, err := New(models.ScanConfig{}).ScanPaths(nil); err == nil {
+			t.Error("Expected an error for an empty path list")
+		}
+	})
+}
+
+func TestScanDir(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.exe"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	result, err := ScanDir(tempDir, models.ScanConfig{
+		MaxFileSizeMB:   10,
+		BlockedPatterns: []string{"*.exe"},
+	})
+	if err != nil {
+		t.Fatalf("ScanDir failed: %v", err)
+	}
+	if result.Progress.ScannedFiles != 2 || result.Progress.BlockedFiles != 1 {
+		t.Errorf("Expected 2 scanned and 1 blocked file, got %d and %d",
+			result.Progress.ScannedFiles, result.Progress.BlockedFiles)
+	}
+
+	if _, err := ScanDir(filepath.Join(tempDir, "missing"), models.ScanConfig{}); !errors.Is(err, ErrRootNotFound) {
+		t.Errorf("Expected ErrRootNotFound, got %v", err)
+	}
+}
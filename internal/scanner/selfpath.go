@@ -0,0 +1,30 @@
+package scanner
+
+import "path/filepath"
+
+// addSelfPath records a file or directory written by the scanner so the walk
+// skips it, even when it lies inside the scanned tree
+func (s *Scanner) addSelfPath(path string) {
+	if s.selfPaths == nil {
+		s.selfPaths = make(map[string]struct{})
+	}
+	s.selfPaths[s.absPath(path)] = struct{}{}
+}
+
+// isSelfPath reports whether path was written by the scanner itself
+func (s *Scanner) isSelfPath(path string) bool {
+	if len(s.selfPaths) == 0 {
+		return false
+	}
+	_, ok := s.selfPaths[s.absPath(path)]
+	return ok
+}
+
+// absPath makes path absolute against the working directory captured when
+// the scan started, avoiding a Getwd call per entry
+func (s *Scanner) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(s.cwd, path)
+}
@@ -0,0 +1,183 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync/atomic"
+
+	"filesystem-logger/internal/models"
+)
+
+// sequential reports whether the tree is walked on a single goroutine
+func (s *Scanner) sequential() bool {
+	return s.config.TraversalMode == models.TraversalSequential
+}
+
+// walkSequential scans every root depth-first on the calling goroutine. It
+// shares listDir, visitEntry and processWork with the parallel mode, which
+// process files directly instead of queueing them. Worker stats are not
+// collected in this mode.
+func (s *Scanner) walkSequential(ctx context.Context, roots []string) error {
+	for _, root := range roots {
+		if s.explicitPaths {
+			atomic.AddInt64(&s.progress.TotalFiles, 1)
+			s.processWork(ctx, pathWork(root), nil)
+			continue
+		}
+
+		info, err := s.statRoot(root)
+		if err != nil {
+			return fmt.Errorf("scan error: %w", err)
+		}
+
+		if !info.IsDir() {
+			s.processWork(ctx, models.ScanWork{Path: root, Root: root, Priority: 1}, nil)
+			continue
+		}
+		s.walkTree(ctx, models.FileInfo{
+			Path:        root,
+			Name:        filepath.Base(root),
+			IsDirectory: true,
+			EntryType:   models.EntryDir,
+		}, root, nil)
+	}
+	return nil
+}
+
+// treeWalk is a single fs.WalkDir pass over the tree below a directory. It
+// serves as the fs.FS of the walk, so directories are listed through the
+// scanner's file system and sftp:// roots and injected failures work as in
+// the parallel mode.
+//
+// WalkDir lists a directory right after visiting it and visits entries in
+// lexical order, so the directory being listed is always the one on top of
+// the stack, and a directory is complete once an entry outside it is
+// visited.
+type treeWalk struct {
+	s     *Scanner
+	ctx   context.Context
+	start models.FileInfo
+	root  string
+	chain []string
+	stack []openDir
+}
+
+// openDir is a directory of a treeWalk whose entries are still visited
+type openDir struct {
+	info  models.FileInfo
+	key   string
+	abs   string
+	chain []string
+}
+
+// walkTree walks dir and the tree below it with fs.WalkDir. chain holds the
+// real paths of the directories above dir when symlinked directories are
+// followed; WalkDir does not descend into links, so every followed link
+// gets a walk of its own.
+func (s *Scanner) walkTree(ctx context.Context, dir models.FileInfo, root string, chain []string) {
+	w := &treeWalk{s: s, ctx: ctx, start: dir, root: root, chain: chain}
+	if err := fs.WalkDir(w, filepath.ToSlash(dir.Path), w.visit); err != nil {
+		s.errorChan <- fmt.Errorf("error reading directory %s: %w", dir.Path, err)
+		return
+	}
+	// Een afgebroken walk laat zijn directories onvolledig achter
+	if ctx.Err() != nil {
+		return
+	}
+	w.leave("")
+}
+
+// visit is the fs.WalkDir callback
+func (w *treeWalk) visit(name string, d fs.DirEntry, err error) error {
+	if w.ctx.Err() != nil {
+		return fs.SkipAll
+	}
+	if err != nil {
+		// Listing errors are reported by ReadDir; only a failing root is left
+		if d == nil {
+			return err
+		}
+		return nil
+	}
+
+	if len(w.stack) == 0 {
+		if !w.enter(w.start, w.chain) {
+			return fs.SkipDir
+		}
+		return nil
+	}
+
+	w.leave(filepath.Dir(filepath.FromSlash(name)))
+	parent := w.stack[len(w.stack)-1]
+	sub, ok := w.s.visitEntry(w.ctx, parent.info.Path, parent.abs, d, w.root, parent.chain)
+	if !ok {
+		return fs.SkipAll
+	}
+	if sub == nil {
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	}
+
+	w.s.checkpoint.hold(parent.abs)
+	if !d.IsDir() {
+		// Gevolgde symlink naar een directory
+		w.s.walkTree(w.ctx, *sub, w.root, parent.chain)
+		return nil
+	}
+	if !w.enter(*sub, parent.chain) {
+		return fs.SkipDir
+	}
+	return nil
+}
+
+// enter pushes dir onto the stack, unless an interrupted scan already
+// completed it
+func (w *treeWalk) enter(dir models.FileInfo, chain []string) bool {
+	abs, ok := w.s.enterDir(dir.Path, w.root)
+	if !ok {
+		return false
+	}
+	w.stack = append(w.stack, openDir{
+		info:  dir,
+		key:   filepath.Clean(dir.Path),
+		abs:   abs,
+		chain: w.s.walkChain(chain, dir.Path),
+	})
+	return true
+}
+
+// leave completes the directories on top of the stack until dir, the
+// directory holding the entry visited next, is on top. An empty dir
+// completes every directory.
+func (w *treeWalk) leave(dir string) {
+	for len(w.stack) > 0 {
+		top := w.stack[len(w.stack)-1]
+		if top.key == dir {
+			return
+		}
+		w.stack = w.stack[:len(w.stack)-1]
+		w.s.checkpoint.release(top.abs)
+	}
+}
+
+// ReadDir lists the directory on top of the stack, which fs.WalkDir has
+// just visited
+func (w *treeWalk) ReadDir(name string) ([]fs.DirEntry, error) {
+	dir := w.stack[len(w.stack)-1]
+	return w.s.listDir(dir.info, dir.abs)
+}
+
+// Stat is used by fs.WalkDir for the directory the walk starts in
+func (w *treeWalk) Stat(name string) (fs.FileInfo, error) {
+	return w.s.fs.Stat(filepath.FromSlash(name))
+}
+
+// Open is never called; fs.WalkDir uses ReadDir and Stat when available
+func (w *treeWalk) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errors.ErrUnsupported}
+}
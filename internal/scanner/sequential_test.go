@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestTraversalModes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string][]byte{
+		"readme.txt":           []byte("hello"),
+		"setup.exe":            []byte("MZ"),
+		"big.bin":              make([]byte, 2*1024*1024),
+		"docs/guide.txt":       []byte("guide"),
+		"docs/old/notes.txt":   []byte("notes"),
+		"src/main.go":          []byte("package main"),
+		"src/vendor/tool.exe":  []byte("MZ"),
+		"src/vendor/lib.go":    []byte("package lib"),
+		"empty/.keep":          nil,
+		"images/photo.jpg":     {0xFF, 0xD8, 0xFF, 0xE0},
+		"images/raw/photo.cr2": []byte("raw"),
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scan := func(mode string) *models.ScanResult {
+		scanner := New(models.ScanConfig{
+			MaxFileSizeMB:   1,
+			ScanRecursively: true,
+			BlockedPatterns: []string{"*.exe"},
+			TraversalMode:   mode,
+		})
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan in %s mode failed: %v", mode, err)
+		}
+		return result
+	}
+
+	// Vergelijk op pad, blokkade en type, ongeacht de volgorde
+	summarize := func(result *models.ScanResult) []string {
+		var entries []string
+		for _, file := range result.Files {
+			entries = append(entries, file.Path+"|"+file.BlockReason+"|"+file.MimeType)
+		}
+		sort.Strings(entries)
+		return entries
+	}
+
+	parallel := scan(models.TraversalParallel)
+	sequential := scan(models.TraversalSequential)
+
+	if !reflect.DeepEqual(summarize(parallel), summarize(sequential)) {
+		t.Errorf("Expected both modes to produce the same files:\n%v\n%v",
+			summarize(parallel), summarize(sequential))
+	}
+	if parallel.Progress.BlockedFiles != sequential.Progress.BlockedFiles ||
+		parallel.Progress.TotalFiles != sequential.Progress.TotalFiles {
+		t.Errorf("Expected equal counters, got %+v and %+v", parallel.Progress, sequential.Progress)
+	}
+
+	t.Run("Sequential order", func(t *testing.T) {
+		var expected []string
+		filepath.WalkDir(tempDir, func(path string, d fs.DirEntry, err error) error {
+			expected = append(expected, path)
+			return err
+		})
+
+		var got []string
+		for _, file := range sequential.Files {
+			got = append(got, file.Path)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected filepath.WalkDir order:\n%v\ngot:\n%v", expected, got)
+		}
+	})
+
+	t.Run("Followed symlinked directories", func(t *testing.T) {
+		linkDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(linkDir, "real", "deep"), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(linkDir, "real", "deep", "file.txt"), []byte("data"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		// Een link naar de eigen ouder vormt een lus
+		if err := os.Symlink(filepath.Join(linkDir, "real"), filepath.Join(linkDir, "alias")); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+		if err := os.Symlink(linkDir, filepath.Join(linkDir, "real", "deep", "loop")); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+
+		results := make(map[string][]string)
+		for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+			result, err := New(models.ScanConfig{
+				MaxFileSizeMB:     1,
+				ScanRecursively:   true,
+				FollowSymlinkDirs: true,
+				TraversalMode:     mode,
+			}).Scan(linkDir)
+			if err != nil {
+				t.Fatalf("Scan in %s mode failed: %v", mode, err)
+			}
+			results[mode] = summarize(result)
+		}
+
+		sequential := results[models.TraversalSequential]
+		if !reflect.DeepEqual(results[models.TraversalParallel], sequential) {
+			t.Errorf("Expected both modes to follow the same links:\n%v\n%v", results[models.TraversalParallel], sequential)
+		}
+		aliased := filepath.Join(linkDir, "alias", "deep", "file.txt") + "||text/plain; charset=utf-8"
+		if i := sort.SearchStrings(sequential, aliased); i == len(sequential) || sequential[i] != aliased {
+			t.Errorf("Expected the file to be found through the link, got %v", sequential)
+		}
+	})
+
+	t.Run("Unknown mode", func(t *testing.T) {
+		if _, err := New(models.ScanConfig{TraversalMode: "random"}).Scan(tempDir); err == nil {
+			t.Error("Expected an error for an unknown traversal mode")
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package scanner
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"filesystem-logger/internal/models"
+)
+
+// startSFTPServer serves the local file system over SFTP on a loopback port
+// for user "scan" with password "secret". It returns the address and a
+// known_hosts file trusting the server's host key.
+func startSFTPServer(t *testing.T) (addr, knownHostsPath string) {
+	t.Helper()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatalf("Failed to create host key signer: %v", err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "scan" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTP(conn, config)
+		}
+	}()
+
+	addr = listener.Addr().String()
+	knownHostsPath = filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, signer.PublicKey())
+	if err := os.WriteFile(knownHostsPath, []byte(line+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write known_hosts: %v", err)
+	}
+	return addr, knownHostsPath
+}
+
+// serveSFTP runs the sftp subsystem for every session on conn
+func serveSFTP(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				// Het verzoek is een string met lengteprefix: "sftp"
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if !ok {
+					continue
+				}
+				server, err := sftp.NewServer(channel)
+				if err != nil {
+					channel.Close()
+					return
+				}
+				server.Serve()
+				channel.Close()
+				return
+			}
+		}()
+	}
+}
+
+func TestSFTPRoot(t *testing.T) {
+	addr, knownHosts := startSFTPServer(t)
+
+	tempDir := t.TempDir()
+	testFiles := map[string]string{
+		"notes.txt":     "plain text",
+		"setup.exe":     "MZ binary",
+		"sub/inner.txt": "more text",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:      10,
+		ScanRecursively:    true,
+		BlockedPatterns:    []string{"*.exe"},
+		SFTPKnownHostsPath: knownHosts,
+	})
+	result, err := scanner.Scan("sftp://scan:secret@" + addr + tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	files := make(map[string]models.FileInfo)
+	for _, file := range result.Files {
+		files[file.Path] = file
+	}
+	for name, content := range testFiles {
+		file, ok := files[filepath.Join(tempDir, name)]
+		if !ok {
+			t.Errorf("Expected %s in the result, got %v", name, files)
+			continue
+		}
+		if file.Size != int64(len(content)) {
+			t.Errorf("Expected %s to be %d bytes, got %d", name, len(content), file.Size)
+		}
+		if blocked := strings.HasSuffix(name, ".exe"); file.IsBlocked != blocked {
+			t.Errorf("Expected IsBlocked=%v for %s, reason %q", blocked, name, file.BlockReason)
+		}
+	}
+	if file := files[filepath.Join(tempDir, "notes.txt")]; !strings.HasPrefix(file.MimeType, "text/plain") {
+		t.Errorf("Expected notes.txt to be read remotely as text/plain, got %q", file.MimeType)
+	}
+	if !files[filepath.Join(tempDir, "sub")].IsDirectory {
+		t.Error("Expected the remote subdirectory to be listed")
+	}
+}
+
+func TestSFTPRootRejected(t *testing.T) {
+	addr, knownHosts := startSFTPServer(t)
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		root   string
+		config models.ScanConfig
+	}{
+		{name: "Wrong password", root: "sftp://scan:wrong@" + addr + tempDir,
+			config: models.ScanConfig{SFTPKnownHostsPath: knownHosts}},
+		{name: "Unknown host key", root: "sftp://scan:secret@" + addr + tempDir,
+			config: models.ScanConfig{SFTPKnownHostsPath: filepath.Join(tempDir, "empty_known_hosts")}},
+		{name: "No credentials", root: "sftp://scan@" + addr + tempDir,
+			config: models.ScanConfig{SFTPKnownHostsPath: knownHosts}},
+		{name: "Export next to the root", root: "sftp://scan:secret@" + addr + tempDir,
+			config: models.ScanConfig{SFTPKnownHostsPath: knownHosts, ExportBlockedToJSON: true}},
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "empty_known_hosts"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write known_hosts: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.MaxFileSizeMB = 10
+			if _, err := New(tt.config).Scan(tt.root); err == nil {
+				t.Error("Expected the sftp scan to fail")
+			}
+		})
+	}
+}
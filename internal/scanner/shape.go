@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+
+	"filesystem-logger/internal/models"
+)
+
+// treeShape holds the deepest entry and the widest directory seen so far.
+// Ties go to the lexically smallest path so parallel scans report the same
+// values as sequential ones.
+type treeShape struct {
+	maxDepth    int
+	deepestPath string
+	widestDir   string
+	widestCount int
+}
+
+// recordDepth notes the depth of path below the root it was found under
+func (s *Scanner) recordDepth(path, root string) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return
+	}
+	depth := strings.Count(filepath.ToSlash(rel), "/") + 1
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if depth > s.shape.maxDepth || (depth == s.shape.maxDepth && path < s.shape.deepestPath) {
+		s.shape.maxDepth = depth
+		s.shape.deepestPath = path
+	}
+}
+
+// recordFanOut notes the number of entries read from directory dir
+func (s *Scanner) recordFanOut(dir string, entries int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entries > s.shape.widestCount || (entries == s.shape.widestCount && entries > 0 && dir < s.shape.widestDir) {
+		s.shape.widestCount = entries
+		s.shape.widestDir = dir
+	}
+}
+
+// applyShape copies the tree metrics into result, using result paths when
+// the scan records relative paths
+func (s *Scanner) applyShape(result *models.ScanResult) {
+	t := s.shape
+	result.MaxDepthSeen = t.maxDepth
+	result.DeepestPath = t.deepestPath
+	result.WidestDir = t.widestDir
+	result.WidestDirCount = t.widestCount
+	if s.relBase != "" {
+		if t.deepestPath != "" {
+			result.DeepestPath = s.resultPath(t.deepestPath)
+		}
+		if t.widestDir != "" {
+			result.WidestDir = s.resultPath(t.widestDir)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestTreeShape(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := []string{
+		"top.txt",
+		"a/one.txt",
+		"a/b/c/deep.txt",
+		"wide/1.txt",
+		"wide/2.txt",
+		"wide/3.txt",
+		"wide/4.txt",
+	}
+	for _, name := range testFiles {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+		t.Run(mode, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:   10,
+				ScanRecursively: true,
+				TraversalMode:   mode,
+			})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			if result.MaxDepthSeen != 4 {
+				t.Errorf("Expected max depth 4, got %d", result.MaxDepthSeen)
+			}
+			if expected := filepath.Join(tempDir, "a", "b", "c", "deep.txt"); result.DeepestPath != expected {
+				t.Errorf("Expected deepest path %s, got %s", expected, result.DeepestPath)
+			}
+			if expected := filepath.Join(tempDir, "wide"); result.WidestDir != expected {
+				t.Errorf("Expected widest directory %s, got %s", expected, result.WidestDir)
+			}
+			if result.WidestDirCount != 4 {
+				t.Errorf("Expected widest directory count 4, got %d", result.WidestDirCount)
+			}
+		})
+	}
+}
+
+func TestTreeShapeCountsSkippedDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"small/1.txt", "huge/1.txt", "huge/2.txt", "huge/3.txt"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	// huge wordt overgeslagen, maar telt wel mee voor de fan-out
+	scanner := New(models.ScanConfig{
+		MaxFileSizeMB:   10,
+		ScanRecursively: true,
+		MaxDirEntries:   2,
+	})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if expected := filepath.Join(tempDir, "huge"); result.WidestDir != expected || result.WidestDirCount != 3 {
+		t.Errorf("Expected widest directory %s with 3 entries, got %s with %d", expected, result.WidestDir, result.WidestDirCount)
+	}
+}
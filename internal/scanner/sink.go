@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"fmt"
+
+	"filesystem-logger/internal/models"
+)
+
+// ResultSink receives the files of a scan as they are collected, so results
+// can be stored elsewhere (a database, a message queue) instead of in the
+// result slice. Add and Finish are called from a single goroutine.
+type ResultSink interface {
+	// Add receives one file or directory entry
+	Add(file models.FileInfo) error
+	// Finish receives the final progress once no more files will be added
+	Finish(progress models.ScanProgress) error
+}
+
+// memorySink is the sink Scan uses: it collects files into s.files
+type memorySink struct {
+	s *Scanner
+}
+
+func (m memorySink) Add(file models.FileInfo) error {
+	m.s.mu.Lock()
+	m.s.files = append(m.s.files, file)
+	m.s.mu.Unlock()
+	return nil
+}
+
+func (m memorySink) Finish(models.ScanProgress) error {
+	return nil
+}
+
+// ScanTo scans root like Scan but hands every file to sink instead of
+// keeping them in memory. StreamToFile and OmitBlocked are applied before
+// the sink, and options that rework the collected files afterwards, such
+// as SortResults or PruneEmptyDirs, have no effect. The first error
+// returned by Add is recorded and returned; the scan itself carries on.
+// ExportBlockedToJSON is rejected: the scanner keeps no files to export.
+//
+// Finish is called exactly once whenever ScanTo returns, with the final
+// progress, also when the scan was cancelled or failed to start. When the
+// workers had to be abandoned after CancelTimeout, their results are
+// dropped and no Add follows Finish.
+func (s *Scanner) ScanTo(root string, sink ResultSink) error {
+	if sink == nil {
+		return fmt.Errorf("no result sink provided")
+	}
+
+	var err error
+	var progress models.ScanProgress
+	if s.config.ExportBlockedToJSON {
+		err = fmt.Errorf("exportBlockedToJSON cannot be combined with a result sink")
+		progress = *s.GetProgress()
+	} else {
+		s.sink = sink
+		var result *models.ScanResult
+		result, err = s.Scan(root)
+		if result != nil {
+			progress = result.Progress
+		} else {
+			progress = *s.GetProgress()
+		}
+	}
+
+	if finishErr := sink.Finish(progress); finishErr != nil && err == nil {
+		err = fmt.Errorf("failed to finish result sink: %v", finishErr)
+	}
+	if s.sinkErr != nil && err == nil {
+		err = fmt.Errorf("failed to add result to sink: %w", s.sinkErr)
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+// countingSink counts the files it receives and keeps the final progress
+type countingSink struct {
+	paths    map[string]bool
+	finished int
+	progress models.ScanProgress
+	addErr   error
+}
+
+func (c *countingSink) Add(file models.FileInfo) error {
+	c.paths[file.Path] = true
+	return c.addErr
+}
+
+func (c *countingSink) Finish(progress models.ScanProgress) error {
+	c.finished++
+	c.progress = progress
+	return nil
+}
+
+func TestScanTo(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 5)
+
+	expected, err := New(models.ScanConfig{MaxFileSizeMB: 10}).Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	sink := &countingSink{paths: make(map[string]bool)}
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+	if err := scanner.ScanTo(tempDir, sink); err != nil {
+		t.Fatalf("ScanTo failed: %v", err)
+	}
+
+	if len(sink.paths) != len(expected.Files) {
+		t.Errorf("Expected the sink to receive %d entries, got %d", len(expected.Files), len(sink.paths))
+	}
+	for _, file := range expected.Files {
+		if !sink.paths[file.Path] {
+			t.Errorf("Expected the sink to receive %s", file.Path)
+		}
+	}
+	if sink.finished != 1 {
+		t.Fatalf("Expected Finish to be called once, got %d", sink.finished)
+	}
+	if sink.progress.ScannedFiles != expected.Progress.ScannedFiles {
+		t.Errorf("Expected final progress with %d scanned files, got %d",
+			expected.Progress.ScannedFiles, sink.progress.ScannedFiles)
+	}
+	if partial := scanner.GetPartialResult(); len(partial.Files) != 0 {
+		t.Errorf("Expected the scanner to keep no files, got %d", len(partial.Files))
+	}
+}
+
+func TestScanToAddError(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 3)
+
+	addErr := errors.New("database unavailable")
+	sink := &countingSink{paths: make(map[string]bool), addErr: addErr}
+	err := New(models.ScanConfig{MaxFileSizeMB: 10}).ScanTo(tempDir, sink)
+	if !errors.Is(err, addErr) {
+		t.Errorf("Expected the sink error, got %v", err)
+	}
+	if sink.finished != 1 {
+		t.Errorf("Expected Finish to be called once, got %d", sink.finished)
+	}
+}
+
+func TestScanToFinishesOnError(t *testing.T) {
+	tempDir := t.TempDir()
+	createFiles(t, tempDir, 3)
+
+	tests := []struct {
+		name   string
+		root   string
+		config models.ScanConfig
+	}{
+		{name: "Missing root", root: filepath.Join(tempDir, "missing"), config: models.ScanConfig{MaxFileSizeMB: 10}},
+		{name: "Invalid config", root: tempDir, config: models.ScanConfig{MaxFileSizeMB: 10, BlockedPatterns: []string{"["}}},
+		{name: "Blocked export", root: tempDir, config: models.ScanConfig{MaxFileSizeMB: 10, ExportBlockedToJSON: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &countingSink{paths: make(map[string]bool)}
+			if err := New(tt.config).ScanTo(tt.root, sink); err == nil {
+				t.Error("Expected ScanTo to fail")
+			}
+			if sink.finished != 1 {
+				t.Errorf("Expected Finish to be called once, got %d", sink.finished)
+			}
+			if len(sink.paths) != 0 {
+				t.Errorf("Expected no files in the sink, got %d", len(sink.paths))
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "blocked_files.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected no blocked files export, got %v", err)
+	}
+}
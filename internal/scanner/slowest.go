@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+// recordTiming keeps path among the slowest files when it took longer than
+// the fastest of the current top ReportSlowestFiles. The list is kept
+// ordered from slowest to fastest.
+func (s *Scanner) recordTiming(path string, start time.Time) {
+	n := s.config.ReportSlowestFiles
+	timing := models.FileTiming{Path: path, Duration: time.Since(start)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.slowest) == n && timing.Duration <= s.slowest[n-1].Duration {
+		return
+	}
+	i := len(s.slowest)
+	for i > 0 && s.slowest[i-1].Duration < timing.Duration {
+		i--
+	}
+	if len(s.slowest) < n {
+		s.slowest = append(s.slowest, models.FileTiming{})
+	}
+	copy(s.slowest[i+1:], s.slowest[i:])
+	s.slowest[i] = timing
+}
+
+// applySlowest copies the slowest files into result, using result paths
+// when the scan records relative paths
+func (s *Scanner) applySlowest(result *models.ScanResult) {
+	if len(s.slowest) == 0 {
+		return
+	}
+	result.SlowestFiles = append([]models.FileTiming(nil), s.slowest...)
+	if s.relBase != "" {
+		for i := range result.SlowestFiles {
+			result.SlowestFiles[i].Path = s.resultPath(result.SlowestFiles[i].Path)
+		}
+	}
+}
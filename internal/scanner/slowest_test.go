@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestReportSlowestFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for _, name := range []string{"a.txt", "b.txt", "slow.txt", "c.txt", "d.txt"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+	slowPath := filepath.Join(tempDir, "slow.txt")
+
+	for _, mode := range []string{models.TraversalParallel, models.TraversalSequential} {
+		t.Run(mode, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:      10,
+				ReportSlowestFiles: 3,
+				TraversalMode:      mode,
+			})
+			scanner.fs = &hookFS{before: func(op, name string) error {
+				if op == "stat" && name == slowPath {
+					time.Sleep(50 * time.Millisecond)
+				}
+				return nil
+			}}
+
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			if len(result.SlowestFiles) != 3 {
+				t.Fatalf("Expected 3 slowest files, got %d", len(result.SlowestFiles))
+			}
+			if top := result.SlowestFiles[0]; top.Path != slowPath || top.Duration < 50*time.Millisecond {
+				t.Errorf("Expected %s to be slowest with at least 50ms, got %s after %v", slowPath, top.Path, top.Duration)
+			}
+			for i := 1; i < len(result.SlowestFiles); i++ {
+				if result.SlowestFiles[i].Duration > result.SlowestFiles[i-1].Duration {
+					t.Errorf("Expected slowest files ordered slowest first, got %v", result.SlowestFiles)
+				}
+			}
+		})
+	}
+}
+
+func TestReportSlowestFilesDisabled(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10})
+	result, err := scanner.Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if result.SlowestFiles != nil {
+		t.Errorf("Expected no slowest files without ReportSlowestFiles, got %v", result.SlowestFiles)
+	}
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+// fileState is what the state file remembers of a file between runs
+type fileState struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// scanState compares files with the state persisted by the previous run and
+// collects the state to persist for the next one. Apart from skip it is only
+// used by the result collector, so only the skipped paths need locking.
+type scanState struct {
+	previous map[string]fileState
+	current  map[string]fileState
+
+	// skipped holds the files and directories the walk deliberately did
+	// not look at; what the previous run saw below them is carried over
+	mu      sync.Mutex
+	skipped map[string]bool
+}
+
+// loadState reads the state file at path. A missing file means there is no
+// previous run to compare with.
+func loadState(path string) (*scanState, error) {
+	st := &scanState{current: make(map[string]fileState), skipped: make(map[string]bool)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %v", err)
+	}
+	if err := json.Unmarshal(data, &st.previous); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %v", err)
+	}
+	if st.previous == nil {
+		st.previous = make(map[string]fileState)
+	}
+	return st, nil
+}
+
+// observe records file under its absolute path and, when there was a
+// previous run, sets its ChangeType. Directories are not tracked.
+func (st *scanState) observe(path string, file *models.FileInfo) {
+	if st == nil || file.IsDirectory {
+		return
+	}
+	current := fileState{Size: file.Size, ModTime: file.ModTime}
+	st.current[path] = current
+
+	if st.previous == nil {
+		return
+	}
+	previous, ok := st.previous[path]
+	switch {
+	case !ok:
+		file.ChangeType = models.ChangeAdded
+	case previous.Size != current.Size || !previous.ModTime.Equal(current.ModTime):
+		file.ChangeType = models.ChangeModified
+	default:
+		file.ChangeType = models.ChangeUnchanged
+	}
+}
+
+// skip records that the file or directory at the absolute path was not
+// looked at on purpose, such as a directory completed by an interrupted scan,
+// one with too many entries or a file left out by sampling. Files of the
+// previous run at or below it are kept instead of reported as deleted.
+func (st *scanState) skip(path string) {
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.skipped[path] = true
+}
+
+// isSkipped reports whether path or one of the directories above it was
+// skipped
+func (st *scanState) isSkipped(path string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for {
+		if st.skipped[path] {
+			return true
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return false
+		}
+		path = parent
+	}
+}
+
+// deleted returns the files of the previous run below one of roots that
+// this run neither saw nor skipped, sorted by path. Roots are absolute
+// paths.
+func (st *scanState) deleted(roots []string) []string {
+	if st == nil {
+		return nil
+	}
+	var paths []string
+	for path := range st.previous {
+		if _, ok := st.current[path]; ok || st.isSkipped(path) {
+			continue
+		}
+		for _, root := range roots {
+			if isWithin(path, root) {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// reportDeleted stores the files of the previous run below roots that this
+// run did not find in result
+func (s *Scanner) reportDeleted(result *models.ScanResult, roots []string) {
+	abs := make([]string, len(roots))
+	for i, root := range roots {
+		abs[i] = s.absPath(root)
+	}
+	for _, path := range s.state.deleted(abs) {
+		if s.relBase != "" {
+			path = s.resultPath(path)
+		}
+		result.DeletedFiles = append(result.DeletedFiles, path)
+	}
+}
+
+// save replaces the state file at path with the files seen by this run and
+// the files of the previous run it skipped. The state is written to a
+// temporary file first so an interrupted write never leaves a truncated
+// state behind.
+func (st *scanState) save(path string) error {
+	if st == nil {
+		return nil
+	}
+	for file, state := range st.previous {
+		if _, ok := st.current[file]; !ok && st.isSkipped(file) {
+			st.current[file] = state
+		}
+	}
+	data, err := json.Marshal(st.current)
+	if err != nil {
+		return fmt.Errorf("failed to encode state file: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestStatePath(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+	statePath := filepath.Join(tempDir, "scan.state")
+
+	for _, name := range []string{"a.txt", "b.txt", "sub/c.txt"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	config := models.ScanConfig{MaxFileSizeMB: 10, ScanRecursively: true, StatePath: statePath}
+	var deleted []string
+	scan := func() map[string]string {
+		t.Helper()
+		result, err := New(config).Scan(root)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		deleted = result.DeletedFiles
+		changes := make(map[string]string)
+		for _, file := range result.Files {
+			if !file.IsDirectory {
+				rel, _ := filepath.Rel(root, file.Path)
+				changes[filepath.ToSlash(rel)] = file.ChangeType
+			}
+		}
+		return changes
+	}
+
+	// Eerste run: geen vorige staat om mee te vergelijken
+	for name, change := range scan() {
+		if change != "" {
+			t.Errorf("Expected no change type for %s on the first run, got %s", name, change)
+		}
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("Expected the state file to be written: %v", err)
+	}
+
+	changed := filepath.Join(root, "b.txt")
+	if err := os.WriteFile(changed, []byte("changed"), 0644); err != nil {
+		t.Fatalf("Failed to change test file: %v", err)
+	}
+	modTime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(changed, modTime, modTime); err != nil {
+		t.Fatalf("Failed to touch test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "d.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "sub", "c.txt")); err != nil {
+		t.Fatalf("Failed to remove test file: %v", err)
+	}
+
+	expected := map[string]string{
+		"a.txt": models.ChangeUnchanged,
+		"b.txt": models.ChangeModified,
+		"d.txt": models.ChangeAdded,
+	}
+	changes := scan()
+	for name, change := range expected {
+		if changes[name] != change {
+			t.Errorf("Expected %s to be %s, got %q", name, change, changes[name])
+		}
+	}
+	if len(changes) != len(expected) {
+		t.Errorf("Expected %d files, got %v", len(expected), changes)
+	}
+	if removed := filepath.Join(root, "sub", "c.txt"); len(deleted) != 1 || deleted[0] != removed {
+		t.Errorf("Expected %s to be reported as deleted, got %v", removed, deleted)
+	}
+
+	t.Run("Unchanged tree", func(t *testing.T) {
+		for name, change := range scan() {
+			if change != models.ChangeUnchanged {
+				t.Errorf("Expected %s to be unchanged on a repeated run, got %s", name, change)
+			}
+		}
+		if len(deleted) != 0 {
+			t.Errorf("Expected no deleted files on a repeated run, got %v", deleted)
+		}
+	})
+}
+
+func TestStateKeepsSkippedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "data")
+
+	for _, name := range []string{"a/x.txt", "a/deeper/v.txt", "b/y.txt", "b/z.txt", "b/w.txt"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config func(*models.ScanConfig)
+	}{
+		{name: "Resumed from checkpoint", config: func(c *models.ScanConfig) {
+			// Een onderbroken scan heeft a al afgerond
+			c.CheckpointPath = filepath.Join(tempDir, "scan.checkpoint")
+			c.ResumeFromCheckpoint = true
+			line := `"` + filepath.ToSlash(filepath.Join(root, "a")) + `"` + "\n"
+			if err := os.WriteFile(c.CheckpointPath, []byte(filepath.FromSlash(line)), 0644); err != nil {
+				t.Fatalf("Failed to write checkpoint: %v", err)
+			}
+		}},
+		{name: "Too many entries", config: func(c *models.ScanConfig) { c.MaxDirEntries = 2 }},
+		{name: "Sampled out", config: func(c *models.ScanConfig) { c.MaxPerExtension = 1 }},
+		{name: "Rejected by callback", config: func(c *models.ScanConfig) {
+			c.ShouldProcess = func(path string, info os.FileInfo) bool { return info.Name() != "a" }
+		}},
+		{name: "Non-recursive", config: func(c *models.ScanConfig) { c.ScanRecursively = false }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statePath := filepath.Join(t.TempDir(), "scan.state")
+			full := models.ScanConfig{
+				MaxFileSizeMB:   10,
+				ScanRecursively: true,
+				TraversalMode:   models.TraversalSequential,
+				StatePath:       statePath,
+			}
+			if _, err := New(full).Scan(root); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			partial := full
+			tt.config(&partial)
+			result, err := New(partial).Scan(root)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if len(result.DeletedFiles) != 0 {
+				t.Errorf("Expected skipped files not to be reported as deleted, got %v", result.DeletedFiles)
+			}
+
+			// De volgende volledige run kent de overgeslagen bestanden nog
+			result, err = New(full).Scan(root)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			for _, file := range result.Files {
+				if !file.IsDirectory && file.ChangeType != models.ChangeUnchanged {
+					t.Errorf("Expected %s to be unchanged after the partial run, got %q", file.Path, file.ChangeType)
+				}
+			}
+		})
+	}
+}
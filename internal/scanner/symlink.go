@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"filesystem-logger/internal/models"
+)
+
+// walkChain appends the real path of dir to the directories on the current
+// walk path. The chain is only kept when symlinked directories are followed,
+// where it is used to detect loops.
+func (s *Scanner) walkChain(chain []string, dir string) []string {
+	if !s.config.FollowSymlinkDirs {
+		return nil
+	}
+	real, err := s.fs.EvalSymlinks(dir)
+	if err != nil {
+		real = dir
+	}
+	// Copy so sibling walks never share a backing array
+	return append(chain[:len(chain):len(chain)], real)
+}
+
+// resolveSymlink returns the info of the target of the symlink at path when
+// it should be followed. Otherwise it returns nil and, when following was
+// attempted, a note explaining why the link was not followed.
+func (s *Scanner) resolveSymlink(path string, chain []string) (target os.FileInfo, note string) {
+	if !s.config.FollowSymlinkDirs && !s.config.FollowSymlinkFiles {
+		return nil, ""
+	}
+
+	target, err := s.fs.Stat(path)
+	if err != nil {
+		return nil, fmt.Sprintf("Symlink not followed: %v", err)
+	}
+
+	if !target.IsDir() {
+		if !s.config.FollowSymlinkFiles {
+			return nil, ""
+		}
+		return target, ""
+	}
+
+	if !s.config.FollowSymlinkDirs {
+		return nil, ""
+	}
+	real, err := s.fs.EvalSymlinks(path)
+	if err != nil {
+		return nil, fmt.Sprintf("Symlink not followed: %v", err)
+	}
+	for _, dir := range chain {
+		if dir == real {
+			return nil, fmt.Sprintf("Symlink loop to %s not followed", real)
+		}
+	}
+	return target, ""
+}
+
+// brokenSymlink reports whether the target of the symlink at path does not
+// exist, with a note naming the missing target
+func (s *Scanner) brokenSymlink(path string) (bool, string) {
+	if _, err := s.fs.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return false, ""
+	}
+	atomic.AddInt64(&s.progress.BrokenSymlinks, 1)
+	if target, err := s.fs.Readlink(path); err == nil {
+		return true, fmt.Sprintf("Broken symlink: target %s does not exist", target)
+	}
+	return true, "Broken symlink: target does not exist"
+}
+
+// emitSymlink reports a symlink that is not followed as an entry of its own.
+// The block rules run on the link's own name and path, so a link named
+// payload.exe is blocked like the file it pretends to be.
+func (s *Scanner) emitSymlink(path, root string, link os.FileInfo, note string, broken bool) {
+	file := models.FileInfo{
+		Path:           path,
+		Name:           link.Name(),
+		Size:           link.Size(),
+		ModTime:        link.ModTime(),
+		Extension:      strings.ToLower(filepath.Ext(link.Name())),
+		IsSymlink:      true,
+		BrokenSymlink:  broken,
+		EntryType:      models.EntrySymlink,
+		SuspiciousName: isSuspiciousName(link.Name()),
+	}
+
+	s.judge(&file, root)
+	// Een notitie van de allowlist gaat voor
+	if file.Note == "" {
+		file.Note = note
+	}
+	if file.IsBlocked {
+		atomic.AddInt64(&s.progress.BlockedFiles, 1)
+	}
+
+	s.resultChan <- models.ScanWorkResult{FileInfo: file}
+	atomic.AddInt64(&s.progress.TotalFiles, 1)
+}
@@ -0,0 +1,249 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestSymlinkHandling(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// root/file.txt, root/sub/inner.txt, root/sub/loop -> root,
+	// root/link.txt -> file.txt en root/linkdir -> sub
+	sub := filepath.Join(tempDir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, path := range []string{filepath.Join(tempDir, "file.txt"), filepath.Join(sub, "inner.txt")} {
+		if err := os.WriteFile(path, []byte("plain text"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	links := map[string]string{
+		filepath.Join(tempDir, "link.txt"): filepath.Join(tempDir, "file.txt"),
+		filepath.Join(tempDir, "linkdir"):  sub,
+		filepath.Join(sub, "loop"):         tempDir,
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		followDirs  bool
+		followFiles bool
+	}{
+		{name: "Follow nothing"},
+		{name: "Follow files only", followFiles: true},
+		{name: "Follow directories only", followDirs: true},
+		{name: "Follow both", followDirs: true, followFiles: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := New(models.ScanConfig{
+				MaxFileSizeMB:      10,
+				ScanRecursively:    true,
+				FollowSymlinkDirs:  tt.followDirs,
+				FollowSymlinkFiles: tt.followFiles,
+			})
+			result, err := scanner.Scan(tempDir)
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			files := make(map[string]models.FileInfo)
+			for _, file := range result.Files {
+				rel, _ := filepath.Rel(tempDir, file.Path)
+				files[rel] = file
+			}
+
+			linkFile, ok := files["link.txt"]
+			if !ok || !linkFile.IsSymlink {
+				t.Fatalf("Expected link.txt to be reported as a symlink, got %+v", linkFile)
+			}
+			if followed := linkFile.MimeType != ""; followed != tt.followFiles {
+				t.Errorf("Expected link.txt followed=%v, got MIME type %q", tt.followFiles, linkFile.MimeType)
+			}
+
+			linkDir := files["linkdir"]
+			if !linkDir.IsSymlink || linkDir.IsDirectory != tt.followDirs {
+				t.Errorf("Expected linkdir to be a symlink with IsDirectory=%v, got %+v", tt.followDirs, linkDir)
+			}
+			if _, descended := files[filepath.Join("linkdir", "inner.txt")]; descended != tt.followDirs {
+				t.Errorf("Expected linkdir descended=%v", tt.followDirs)
+			}
+
+			// De lus terug naar de root wordt nooit gevolgd
+			for _, loop := range []string{filepath.Join("sub", "loop"), filepath.Join("linkdir", "loop")} {
+				if file, ok := files[loop]; ok && file.IsDirectory {
+					t.Errorf("Expected %s not to be followed", loop)
+				}
+			}
+			if tt.followDirs && files[filepath.Join("sub", "loop")].Note == "" {
+				t.Error("Expected a note explaining the skipped loop")
+			}
+			if len(result.Progress.Errors) > 0 {
+				t.Errorf("Expected no errors, got %v", result.Progress.Errors)
+			}
+		})
+	}
+}
+
+func TestBrokenSymlinks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("plain text"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(tempDir, "file.txt"), filepath.Join(tempDir, "good")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	missing := filepath.Join(tempDir, "missing.txt")
+	if err := os.Symlink(missing, filepath.Join(tempDir, "dangling")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	for _, follow := range []bool{false, true} {
+		scanner := New(models.ScanConfig{
+			MaxFileSizeMB:      10,
+			FailOnError:        true,
+			FollowSymlinkDirs:  follow,
+			FollowSymlinkFiles: follow,
+		})
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Expected broken symlinks not to fail the scan, got %v", err)
+		}
+
+		files := make(map[string]models.FileInfo)
+		for _, file := range result.Files {
+			files[file.Name] = file
+		}
+
+		dangling := files["dangling"]
+		if !dangling.BrokenSymlink || !dangling.IsSymlink {
+			t.Errorf("Expected dangling to be flagged as a broken symlink, got %+v", dangling)
+		}
+		if !strings.Contains(dangling.Note, missing) {
+			t.Errorf("Expected the note to name the missing target, got %q", dangling.Note)
+		}
+		if files["good"].BrokenSymlink {
+			t.Error("Expected good not to be flagged")
+		}
+		if result.Progress.BrokenSymlinks != 1 {
+			t.Errorf("Expected 1 broken symlink, got %d", result.Progress.BrokenSymlinks)
+		}
+	}
+}
+
+func TestResolveRealPaths(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// data is een symlink naar real/data
+	realDir := filepath.Join(tempDir, "real", "data")
+	if err := os.MkdirAll(filepath.Join(realDir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"file.txt", "sub/inner.txt"} {
+		if err := os.WriteFile(filepath.Join(realDir, name), []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+	root := filepath.Join(tempDir, "data")
+	if err := os.Symlink(realDir, root); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	scanner := New(models.ScanConfig{MaxFileSizeMB: 10, ScanRecursively: true, ResolveRealPaths: true})
+	result, err := scanner.Scan(root)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	files := 0
+	for _, file := range result.Files {
+		if file.IsDirectory {
+			continue
+		}
+		files++
+		if file.RealPath == "" || file.RealPath == file.Path {
+			t.Errorf("Expected a real path different from %s, got %q", file.Path, file.RealPath)
+			continue
+		}
+		viaLink, err := os.Stat(file.Path)
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", file.Path, err)
+		}
+		viaReal, err := os.Lstat(file.RealPath)
+		if err != nil {
+			t.Fatalf("Failed to stat real path %s: %v", file.RealPath, err)
+		}
+		if !os.SameFile(viaLink, viaReal) {
+			t.Errorf("Expected %s and %s to be the same file", file.Path, file.RealPath)
+		}
+	}
+	if files != 2 {
+		t.Errorf("Expected 2 files, got %d", files)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		result, err := New(models.ScanConfig{MaxFileSizeMB: 10}).Scan(root)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		for _, file := range result.Files {
+			if file.RealPath != "" {
+				t.Errorf("Expected no real path for %s without ResolveRealPaths, got %s", file.Path, file.RealPath)
+			}
+		}
+	})
+}
+
+func TestSymlinkBlockedByName(t *testing.T) {
+	tempDir := t.TempDir()
+	target := filepath.Join(tempDir, "notes.txt")
+	if err := os.WriteFile(target, []byte("plain text"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Symlink(target, filepath.Join(tempDir, "payload.exe")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(tempDir, "missing"), filepath.Join(tempDir, "broken.exe")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	for _, follow := range []bool{false, true} {
+		scanner := New(models.ScanConfig{
+			MaxFileSizeMB:      10,
+			BlockedPatterns:    []string{"*.exe"},
+			FollowSymlinkFiles: follow,
+		})
+		result, err := scanner.Scan(tempDir)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+
+		blocked := make(map[string]bool)
+		for _, file := range result.Files {
+			blocked[file.Name] = file.IsBlocked
+		}
+		if !blocked["payload.exe"] || !blocked["broken.exe"] {
+			t.Errorf("Expected symlinked *.exe to be blocked with FollowSymlinkFiles=%v, got %v", follow, blocked)
+		}
+		if blocked["notes.txt"] {
+			t.Errorf("Expected notes.txt to stay allowed with FollowSymlinkFiles=%v", follow)
+		}
+		if result.Progress.BlockedFiles != 2 {
+			t.Errorf("Expected 2 blocked files with FollowSymlinkFiles=%v, got %d", follow, result.Progress.BlockedFiles)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package csvexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+var header = []string{
+	"path", "name", "size", "extension", "mimeType", "modTime", "blockReason",
+}
+
+// utf8BOM marks a file as UTF-8 for spreadsheet applications such as Excel
+const utf8BOM = "\uFEFF"
+
+// Options controls the encoding of a CSV export
+type Options struct {
+	// CRLF ends lines with \r\n, as Windows consumers expect, instead of \n
+	CRLF bool
+	// BOM prepends a UTF-8 byte order mark so Excel detects the encoding
+	BOM bool
+}
+
+func ExportBlockedFiles(result *models.ScanResult, outputPath string) error {
+	return ExportBlockedFilesWithOptions(result, outputPath, Options{})
+}
+
+// ExportBlockedFilesWithOptions writes the blocked file export to
+// outputPath encoded as selected by opts
+func ExportBlockedFilesWithOptions(result *models.ScanResult, outputPath string, opts Options) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	return WriteBlockedFilesWithOptions(result, file, opts)
+}
+
+// WriteBlockedFiles writes one CSV row per blocked file to w
+func WriteBlockedFiles(result *models.ScanResult, w io.Writer) error {
+	return WriteBlockedFilesWithOptions(result, w, Options{})
+}
+
+// WriteBlockedFilesWithOptions writes one CSV row per blocked file to w
+// encoded as selected by opts
+func WriteBlockedFilesWithOptions(result *models.ScanResult, w io.Writer, opts Options) error {
+	if opts.BOM {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return fmt.Errorf("failed to write CSV: %v", err)
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = opts.CRLF
+
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+
+	for _, file := range result.Files {
+		if !file.IsBlocked {
+			continue
+		}
+		record := []string{
+			file.Path,
+			file.Name,
+			strconv.FormatInt(file.Size, 10),
+			file.Extension,
+			file.MimeType,
+			file.ModTime.Format(time.RFC3339),
+			file.BlockReason,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package csvexport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestWriteBlockedFiles(t *testing.T) {
+	result := &models.ScanResult{
+		Files: []models.FileInfo{
+			{
+				Path:        "/test/file1.txt",
+				Name:        "file1.txt",
+				Size:        1024,
+				IsBlocked:   true,
+				BlockReason: "File size exceeds limit",
+			},
+			{
+				Path: "/test/file2.txt",
+				Name: "file2.txt",
+				Size: 512,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBlockedFiles(result, &buf); err != nil {
+		t.Fatalf("WriteBlockedFiles failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+
+	// header + 1 blocked file
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[1][0] != "/test/file1.txt" || records[1][2] != "1024" {
+		t.Errorf("Unexpected record: %v", records[1])
+	}
+}
+
+func TestWriteBlockedFilesOptions(t *testing.T) {
+	result := &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/test/setup.exe", Name: "setup.exe", Size: 2048, IsBlocked: true, BlockReason: "File type not allowed"},
+			{Path: "/test/big.iso", Name: "big.iso", Size: 4096, IsBlocked: true, BlockReason: "File size exceeds limit"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "Default", opts: Options{}},
+		{name: "CRLF", opts: Options{CRLF: true}},
+		{name: "BOM", opts: Options{BOM: true}},
+		{name: "CRLF and BOM", opts: Options{CRLF: true, BOM: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteBlockedFilesWithOptions(result, &buf, tt.opts); err != nil {
+				t.Fatalf("WriteBlockedFilesWithOptions failed: %v", err)
+			}
+			data := buf.Bytes()
+
+			if hasBOM := bytes.HasPrefix(data, []byte(utf8BOM)); hasBOM != tt.opts.BOM {
+				t.Errorf("Expected BOM=%v, got %v", tt.opts.BOM, hasBOM)
+			}
+			lines := bytes.Count(data, []byte("\n"))
+			crlfLines := bytes.Count(data, []byte("\r\n"))
+			if lines != 3 {
+				t.Errorf("Expected 3 lines, got %d", lines)
+			}
+			if tt.opts.CRLF && crlfLines != lines {
+				t.Errorf("Expected every line to end with CRLF, got %d of %d", crlfLines, lines)
+			}
+			if !tt.opts.CRLF && crlfLines != 0 {
+				t.Errorf("Expected LF line endings, got %d CRLF", crlfLines)
+			}
+
+			records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM)))).ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to parse CSV: %v", err)
+			}
+			if len(records) != 3 || records[0][0] != "path" || records[2][0] != "/test/big.iso" {
+				t.Errorf("Expected the header and both blocked files, got %v", records)
+			}
+		})
+	}
+}
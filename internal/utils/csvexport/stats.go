@@ -0,0 +1,77 @@
+package csvexport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+var statsHeader = []string{
+	"timestamp", "totalFiles", "blockedFiles", "totalSize", "blockedSize", "duration",
+}
+
+// statsMu serializes appends from within this process
+var statsMu sync.Mutex
+
+// ExportStatsCSV appends one row summarizing result to the CSV file at
+// path, creating it with a header first when it does not exist. The
+// duration column holds seconds. Each row is written with a single
+// O_APPEND write, so concurrent appends from other processes do not
+// interleave.
+func ExportStatsCSV(result *models.ScanResult, path string) error {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	// Alleen wie het bestand aanmaakt schrijft de header
+	created := true
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		created = false
+		file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open stats file: %v", err)
+	}
+	defer file.Close()
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if created {
+		writer.Write(statsHeader)
+	}
+	writer.Write(statsRecord(result, time.Now()))
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV record: %v", err)
+	}
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write stats file: %v", err)
+	}
+	return nil
+}
+
+// statsRecord summarizes result as one stats row
+func statsRecord(result *models.ScanResult, now time.Time) []string {
+	return []string{
+		now.Format(time.RFC3339),
+		strconv.FormatInt(result.Progress.TotalFiles, 10),
+		strconv.FormatInt(result.Progress.BlockedFiles, 10),
+		strconv.FormatInt(result.Progress.TotalSize, 10),
+		strconv.FormatInt(result.Progress.BlockedSize, 10),
+		strconv.FormatFloat(result.Duration.Seconds(), 'f', 3, 64),
+	}
+}
@@ -0,0 +1,72 @@
+package csvexport
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestExportStatsCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats", "scans.csv")
+
+	results := []*models.ScanResult{
+		{
+			// Geblokkeerde bestanden weggelaten, zoals met OmitBlocked
+			Files:    []models.FileInfo{{Path: "/data/small.txt", Size: 10}},
+			Progress: models.ScanProgress{TotalFiles: 2, BlockedFiles: 1, TotalSize: 4106, BlockedSize: 4096},
+			Duration: 1500 * time.Millisecond,
+		},
+		{
+			Files:    []models.FileInfo{{Path: "/data/small.txt", Size: 10}},
+			Progress: models.ScanProgress{TotalFiles: 1, TotalSize: 10},
+			Duration: 2 * time.Second,
+		},
+	}
+	for _, result := range results {
+		if err := ExportStatsCSV(result, path); err != nil {
+			t.Fatalf("ExportStatsCSV failed: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open stats file: %v", err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse stats file: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %d records: %v", len(records), records)
+	}
+	if !reflect.DeepEqual(records[0], statsHeader) {
+		t.Errorf("Expected header %v, got %v", statsHeader, records[0])
+	}
+
+	tests := []struct {
+		name     string
+		record   []string
+		expected []string
+	}{
+		{name: "First scan", record: records[1], expected: []string{"2", "1", "4106", "4096", "1.500"}},
+		{name: "Second scan", record: records[2], expected: []string{"1", "0", "10", "0", "2.000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := time.Parse(time.RFC3339, tt.record[0]); err != nil {
+				t.Errorf("Expected an RFC 3339 timestamp, got %q", tt.record[0])
+			}
+			if !reflect.DeepEqual(tt.record[1:], tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.record[1:])
+			}
+		})
+	}
+}
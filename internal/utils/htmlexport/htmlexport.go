@@ -0,0 +1,140 @@
+package htmlexport
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"filesystem-logger/internal/models"
+	"filesystem-logger/internal/utils/jsonexport"
+)
+
+// largestFileCount is how many of the largest files the report lists
+const largestFileCount = 10
+
+// reasonCount is one row of the blocked-by-reason summary
+type reasonCount struct {
+	Reason string
+	Count  int
+}
+
+// reportData is what the report template renders
+type reportData struct {
+	jsonexport.ExportData
+	Largest  []models.FileInfo
+	ByReason []reasonCount
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"formatTime": func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Scan report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+td.num { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Scan report</h1>
+<p>Generated {{formatTime .Timestamp}}{{if .Root}} for {{.Root}}{{end}}</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Total files</th><td class="num">{{.TotalFiles}}</td></tr>
+<tr><th>Total size</th><td class="num">{{.TotalSize}} bytes</td></tr>
+<tr><th>Blocked files</th><td class="num">{{.BlockedCount}}</td></tr>
+<tr><th>Blocked size</th><td class="num">{{.BlockedSize}} bytes</td></tr>
+<tr><th>Duration</th><td class="num">{{.ScanDuration}}</td></tr>
+</table>
+
+{{if .Largest}}<h2>Largest files</h2>
+<table>
+<tr><th>Path</th><th>Size</th></tr>
+{{range .Largest}}<tr><td>{{.Path}}</td><td class="num">{{.Size}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .ByReason}}<h2>Blocked by reason</h2>
+<table>
+<tr><th>Reason</th><th>Files</th></tr>
+{{range .ByReason}}<tr><td>{{.Reason}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Blocked files</h2>
+{{if .BlockedFiles}}<table>
+<tr><th>Path</th><th>Size</th><th>Type</th><th>Reason</th></tr>
+{{range .BlockedFiles}}<tr><td>{{.Path}}</td><td class="num">{{.Size}}</td><td>{{.FileType}}</td><td>{{.BlockReason}}</td></tr>
+{{end}}</table>
+{{else}}<p>No files were blocked.</p>
+{{end}}</body>
+</html>
+`))
+
+// ExportHTMLReport writes a self-contained HTML report of result to w: a
+// summary of the counts, the largest files, the blocked files grouped by
+// reason and a table of every blocked file
+func ExportHTMLReport(result *models.ScanResult, w io.Writer) error {
+	data := reportData{
+		ExportData: jsonexport.BuildExportData(result),
+		Largest:    largestFiles(result.Files, largestFileCount),
+		ByReason:   blockedByReason(result),
+	}
+	if err := reportTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %v", err)
+	}
+	return nil
+}
+
+// largestFiles returns up to n files, excluding directories, largest first
+func largestFiles(files []models.FileInfo, n int) []models.FileInfo {
+	var largest []models.FileInfo
+	for _, file := range files {
+		if !file.IsDirectory {
+			largest = append(largest, file)
+		}
+	}
+	sort.SliceStable(largest, func(i, j int) bool {
+		return largest[i].Size > largest[j].Size
+	})
+	if len(largest) > n {
+		largest = largest[:n]
+	}
+	return largest
+}
+
+// blockedByReason counts the blocked files per reason, most common first.
+// It uses the grouping recorded by the scanner and falls back to the exact
+// block reasons for results without one.
+func blockedByReason(result *models.ScanResult) []reasonCount {
+	counts := make(map[string]int)
+	if result.BlockedByReason != nil {
+		for reason, paths := range result.BlockedByReason {
+			counts[reason] = len(paths)
+		}
+	} else {
+		for _, file := range result.Files {
+			if file.IsBlocked {
+				counts[file.BlockReason]++
+			}
+		}
+	}
+
+	rows := make([]reasonCount, 0, len(counts))
+	for reason, count := range counts {
+		rows = append(rows, reasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return rows[i].Reason < rows[j].Reason
+	})
+	return rows
+}
@@ -0,0 +1,46 @@
+package htmlexport
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestExportHTMLReport(t *testing.T) {
+	result := &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/test/setup.exe", Name: "setup.exe", Size: 2048, IsBlocked: true, BlockReason: "File type not allowed"},
+			{Path: "/test/notes.txt", Name: "notes.txt", Size: 100},
+			{Path: "/test/<big>.iso", Name: "<big>.iso", Size: 4096, IsBlocked: true, BlockReason: "File size exceeds limit"},
+		},
+		Progress: models.ScanProgress{TotalFiles: 3, BlockedFiles: 2, TotalSize: 6244},
+		Duration: 2 * time.Second,
+		Root:     "/test",
+	}
+
+	var buf bytes.Buffer
+	if err := ExportHTMLReport(result, &buf); err != nil {
+		t.Fatalf("ExportHTMLReport failed: %v", err)
+	}
+	html := buf.String()
+
+	for _, expected := range []string{
+		`<tr><th>Blocked files</th><td class="num">2</td></tr>`,
+		"<td>/test/setup.exe</td>",
+		"<td>File type not allowed</td>",
+		"&lt;big&gt;.iso",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected the report to contain %q", expected)
+		}
+	}
+	if strings.Contains(html, "<big>") {
+		t.Error("Expected file names to be escaped")
+	}
+	if strings.Index(html, "/test/&lt;big&gt;.iso") > strings.Index(html, "/test/setup.exe") {
+		t.Error("Expected the largest files to be listed largest first")
+	}
+}
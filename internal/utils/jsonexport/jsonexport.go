@@ -3,6 +3,7 @@ package jsonexport
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -12,17 +13,88 @@ import (
 
 type ExportData struct {
 	Timestamp    time.Time         `json:"timestamp"`
+	Root         string            `json:"root,omitempty"`
 	TotalFiles   int64             `json:"totalFiles"`
 	BlockedFiles []models.FileInfo `json:"blockedFiles"`
 	ScanDuration time.Duration     `json:"scanDuration"`
 	BlockedCount int64             `json:"blockedCount"`
 	TotalSize    int64             `json:"totalSize"`
 	BlockedSize  int64             `json:"blockedSize"`
+
+	// HashAlgorithm names the algorithm behind the file hashes, if any
+	HashAlgorithm string `json:"hashAlgorithm,omitempty"`
+
+	// ScanDurationHuman is ScanDuration formatted like "5s"; ScanDuration
+	// stays in nanoseconds for precision
+	ScanDurationHuman string `json:"scanDurationHuman"`
 }
 
 func ExportBlockedFiles(result *models.ScanResult, outputPath string) error {
+	return ExportBlockedFilesWithStyle(result, outputPath, KeyStyleCamel)
+}
+
+// ExportBlockedFilesWithStyle writes the blocked file export to outputPath
+// using the given key style
+func ExportBlockedFilesWithStyle(result *models.ScanResult, outputPath string, style KeyStyle) error {
+	return exportBlockedFiles(result, outputPath, style, true)
+}
+
+// ExportBlockedFilesCompact writes the blocked file export to outputPath as
+// single-line JSON without indentation
+func ExportBlockedFilesCompact(result *models.ScanResult, outputPath string) error {
+	return exportBlockedFiles(result, outputPath, KeyStyleCamel, false)
+}
+
+func exportBlockedFiles(result *models.ScanResult, outputPath string, style KeyStyle, pretty bool) error {
+	// Zorg dat de output directory bestaat
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	// Schrijf naar JSON bestand
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	return writeBlockedFiles(result, file, style, pretty)
+}
+
+// WriteBlockedFiles writes the blocked file export as indented JSON to w
+func WriteBlockedFiles(result *models.ScanResult, w io.Writer) error {
+	return WriteBlockedFilesWithStyle(result, w, KeyStyleCamel)
+}
+
+// WriteBlockedFilesWithStyle writes the blocked file export as indented
+// JSON to w using the given key style
+func WriteBlockedFilesWithStyle(result *models.ScanResult, w io.Writer, style KeyStyle) error {
+	return writeBlockedFiles(result, w, style, true)
+}
+
+// WriteBlockedFilesCompact writes the blocked file export as single-line
+// JSON to w
+func WriteBlockedFilesCompact(result *models.ScanResult, w io.Writer) error {
+	return writeBlockedFiles(result, w, KeyStyleCamel, false)
+}
+
+func writeBlockedFiles(result *models.ScanResult, w io.Writer, style KeyStyle, pretty bool) error {
+	encoder := json.NewEncoder(w)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(withKeyStyle(BuildExportData(result), style)); err != nil {
+		return fmt.Errorf("failed to encode JSON: %v", err)
+	}
+
+	return nil
+}
+
+// BuildExportData aggregates the blocked files of result in memory, without
+// writing anything, for callers that serve or process the export themselves
+func BuildExportData(result *models.ScanResult) ExportData {
 	// Verzamel geblokkeerde bestanden
-	var blockedFiles []models.FileInfo
+	blockedFiles := make([]models.FileInfo, 0)
 	var blockedSize int64
 
 	for _, file := range result.Files {
@@ -33,33 +105,18 @@ func ExportBlockedFiles(result *models.ScanResult, outputPath string) error {
 	}
 
 	// Maak export data
-	exportData := ExportData{
+	return ExportData{
 		Timestamp:    time.Now(),
+		Root:         result.Root,
 		TotalFiles:   result.Progress.TotalFiles,
 		BlockedFiles: blockedFiles,
 		ScanDuration: result.Duration,
 		BlockedCount: int64(len(blockedFiles)),
 		TotalSize:    result.Progress.TotalSize,
 		BlockedSize:  blockedSize,
-	}
 
-	// Zorg dat de output directory bestaat
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %v", err)
-	}
-
-	// Schrijf naar JSON bestand
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %v", err)
-	}
-	defer file.Close()
+		HashAlgorithm: result.HashAlgorithm,
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(exportData); err != nil {
-		return fmt.Errorf("failed to encode JSON: %v", err)
+		ScanDurationHuman: result.Duration.String(),
 	}
-
-	return nil
 }
@@ -1,9 +1,11 @@
 package jsonexport
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -74,3 +76,208 @@ func TestExportBlockedFiles(t *testing.T) {
 		t.Errorf("Expected BlockedSize=1024, got %d", exported.BlockedSize)
 	}
 }
+
+func TestBuildExportData(t *testing.T) {
+	result := &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/test/setup.exe", Name: "setup.exe", Size: 2048, IsBlocked: true, BlockReason: "File type not allowed"},
+			{Path: "/test/notes.txt", Name: "notes.txt", Size: 100},
+			{Path: "/test/big.iso", Name: "big.iso", Size: 4096, IsBlocked: true, BlockReason: "File size exceeds limit"},
+		},
+		Progress: models.ScanProgress{TotalFiles: 3, BlockedFiles: 2, TotalSize: 6244},
+		Duration: 2 * time.Second,
+		Root:     "/test",
+	}
+
+	inMemory := BuildExportData(result)
+	if inMemory.BlockedCount != 2 || inMemory.BlockedSize != 6144 {
+		t.Errorf("Expected 2 blocked files of 6144 bytes, got %d of %d bytes", inMemory.BlockedCount, inMemory.BlockedSize)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "blocked.json")
+	if err := ExportBlockedFiles(result, outputPath); err != nil {
+		t.Fatalf("ExportBlockedFiles failed: %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read exported file: %v", err)
+	}
+	var onDisk ExportData
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatalf("Failed to parse exported JSON: %v", err)
+	}
+
+	// Het tijdstip verschilt per aanroep
+	inMemory.Timestamp = time.Time{}
+	onDisk.Timestamp = time.Time{}
+	if !reflect.DeepEqual(inMemory, onDisk) {
+		t.Errorf("Expected the file to match the in-memory export\nmemory: %+v\ndisk:   %+v", inMemory, onDisk)
+	}
+}
+
+func TestKeyStyle(t *testing.T) {
+	result := &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/test/setup.exe", Name: "setup.exe", Size: 2048, MimeType: "application/x-msdownload", IsBlocked: true, BlockReason: "File type not allowed"},
+		},
+		Progress: models.ScanProgress{TotalFiles: 1, TotalSize: 2048},
+	}
+
+	tests := []struct {
+		style   KeyStyle
+		present []string
+		absent  []string
+	}{
+		{
+			style:   KeyStyleCamel,
+			present: []string{"blockedFiles", "blockReason", "mimeType", "isBlocked", "totalFiles"},
+			absent:  []string{"blocked_files", "block_reason"},
+		},
+		{
+			style:   KeyStyleSnake,
+			present: []string{"blocked_files", "block_reason", "mime_type", "is_blocked", "total_files"},
+			absent:  []string{"blockedFiles", "blockReason"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteBlockedFilesWithStyle(result, &buf, tt.style); err != nil {
+				t.Fatalf("WriteBlockedFilesWithStyle failed: %v", err)
+			}
+
+			var exported map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+				t.Fatalf("Failed to parse exported JSON: %v", err)
+			}
+			keys := make(map[string]bool)
+			for key, value := range exported {
+				keys[key] = true
+				if files, ok := value.([]interface{}); ok && len(files) > 0 {
+					for fileKey := range files[0].(map[string]interface{}) {
+						keys[fileKey] = true
+					}
+				}
+			}
+
+			for _, key := range tt.present {
+				if !keys[key] {
+					t.Errorf("Expected key %s, got %v", key, keys)
+				}
+			}
+			for _, key := range tt.absent {
+				if keys[key] {
+					t.Errorf("Expected no key %s", key)
+				}
+			}
+		})
+	}
+
+	t.Run("No blocked files", func(t *testing.T) {
+		for _, style := range []KeyStyle{KeyStyleCamel, KeyStyleSnake} {
+			var buf bytes.Buffer
+			if err := WriteBlockedFilesWithStyle(&models.ScanResult{}, &buf, style); err != nil {
+				t.Fatalf("WriteBlockedFilesWithStyle failed: %v", err)
+			}
+			if !bytes.Contains(buf.Bytes(), []byte(`: []`)) || bytes.Contains(buf.Bytes(), []byte("null")) {
+				t.Errorf("Expected an empty list of blocked files in style %s, got %s", style, buf.Bytes())
+			}
+		}
+	})
+
+	t.Run("Unknown style", func(t *testing.T) {
+		if _, err := ParseKeyStyle("kebab"); err == nil {
+			t.Error("Expected an error for an unknown key style")
+		}
+	})
+}
+
+func TestExportBlockedFilesCompact(t *testing.T) {
+	result := &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/test/setup.exe", Name: "setup.exe", Size: 2048, IsBlocked: true, BlockReason: "File type not allowed"},
+			{Path: "/test/big.iso", Name: "big.iso", Size: 4096, IsBlocked: true, BlockReason: "File size exceeds limit"},
+		},
+		Progress: models.ScanProgress{TotalFiles: 2, BlockedFiles: 2, TotalSize: 6144},
+		Duration: time.Second,
+		Root:     "/test",
+	}
+
+	tempDir := t.TempDir()
+	prettyPath := filepath.Join(tempDir, "pretty.json")
+	compactPath := filepath.Join(tempDir, "compact.json")
+	if err := ExportBlockedFiles(result, prettyPath); err != nil {
+		t.Fatalf("ExportBlockedFiles failed: %v", err)
+	}
+	if err := ExportBlockedFilesCompact(result, compactPath); err != nil {
+		t.Fatalf("ExportBlockedFilesCompact failed: %v", err)
+	}
+
+	prettyData, err := os.ReadFile(prettyPath)
+	if err != nil {
+		t.Fatalf("Failed to read pretty export: %v", err)
+	}
+	compactData, err := os.ReadFile(compactPath)
+	if err != nil {
+		t.Fatalf("Failed to read compact export: %v", err)
+	}
+
+	if len(compactData) >= len(prettyData) {
+		t.Errorf("Expected compact export smaller than pretty export, got %d >= %d bytes", len(compactData), len(prettyData))
+	}
+	if lines := bytes.Count(bytes.TrimSpace(compactData), []byte("\n")); lines != 0 {
+		t.Errorf("Expected compact export on a single line, got %d line breaks", lines)
+	}
+
+	var pretty, compact ExportData
+	if err := json.Unmarshal(prettyData, &pretty); err != nil {
+		t.Fatalf("Failed to parse pretty export: %v", err)
+	}
+	if err := json.Unmarshal(compactData, &compact); err != nil {
+		t.Fatalf("Failed to parse compact export: %v", err)
+	}
+
+	// Het tijdstip verschilt per aanroep
+	pretty.Timestamp = time.Time{}
+	compact.Timestamp = time.Time{}
+	if !reflect.DeepEqual(pretty, compact) {
+		t.Errorf("Expected both exports to decode equally\npretty:  %+v\ncompact: %+v", pretty, compact)
+	}
+}
+
+func TestScanDurationHuman(t *testing.T) {
+	result := &models.ScanResult{
+		Files:    []models.FileInfo{{Path: "/test/setup.exe", Name: "setup.exe", IsBlocked: true}},
+		Duration: 5*time.Second + 250*time.Millisecond,
+	}
+
+	for _, style := range []KeyStyle{KeyStyleCamel, KeyStyleSnake} {
+		t.Run(string(style), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteBlockedFilesWithStyle(result, &buf, style); err != nil {
+				t.Fatalf("WriteBlockedFilesWithStyle failed: %v", err)
+			}
+			var exported map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+				t.Fatalf("Failed to parse exported JSON: %v", err)
+			}
+
+			numericKey, humanKey := "scanDuration", "scanDurationHuman"
+			if style == KeyStyleSnake {
+				numericKey, humanKey = "scan_duration", "scan_duration_human"
+			}
+			numeric, ok := exported[numericKey].(float64)
+			if !ok || time.Duration(numeric) != result.Duration {
+				t.Errorf("Expected %s %d, got %v", numericKey, result.Duration, exported[numericKey])
+			}
+			human, ok := exported[humanKey].(string)
+			if !ok || human != "5.25s" {
+				t.Fatalf("Expected %s 5.25s, got %v", humanKey, exported[humanKey])
+			}
+			if parsed, err := time.ParseDuration(human); err != nil || parsed != time.Duration(numeric) {
+				t.Errorf("Expected %s to match %s, got %v (%v)", humanKey, numericKey, parsed, err)
+			}
+		})
+	}
+}
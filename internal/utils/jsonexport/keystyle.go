@@ -0,0 +1,122 @@
+package jsonexport
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeyStyle selects the casing of the keys in a JSON export
+type KeyStyle string
+
+const (
+	// KeyStyleCamel uses the camelCase keys of the models, such as blockReason
+	KeyStyleCamel KeyStyle = "camel"
+	// KeyStyleSnake uses snake_case keys, such as block_reason
+	KeyStyleSnake KeyStyle = "snake"
+)
+
+// ParseKeyStyle validates a key style name; an empty name means camelCase
+func ParseKeyStyle(name string) (KeyStyle, error) {
+	switch style := KeyStyle(name); style {
+	case "":
+		return KeyStyleCamel, nil
+	case KeyStyleCamel, KeyStyleSnake:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unknown key style %q", name)
+	}
+}
+
+// snakeExportData mirrors ExportData with snake_case keys
+type snakeExportData struct {
+	Timestamp    time.Time       `json:"timestamp"`
+	Root         string          `json:"root,omitempty"`
+	TotalFiles   int64           `json:"total_files"`
+	BlockedFiles []snakeFileInfo `json:"blocked_files"`
+	ScanDuration time.Duration   `json:"scan_duration"`
+	BlockedCount int64           `json:"blocked_count"`
+	TotalSize    int64           `json:"total_size"`
+	BlockedSize  int64           `json:"blocked_size"`
+
+	HashAlgorithm string `json:"hash_algorithm,omitempty"`
+
+	ScanDurationHuman string `json:"scan_duration_human"`
+}
+
+// snakeFileInfo mirrors models.FileInfo with snake_case keys. It is
+// converted from FileInfo directly, so a field added there fails to compile
+// here until it is added with its snake_case key.
+type snakeFileInfo struct {
+	Path        string    `json:"path"`
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	FileType    string    `json:"file_type"`
+	MimeType    string    `json:"mime_type"`
+	Category    string    `json:"category,omitempty"`
+	Extension   string    `json:"extension"`
+	ModTime     time.Time `json:"mod_time"`
+	CreatedTime time.Time `json:"created_time"`
+	IsDirectory bool      `json:"is_directory"`
+	IsBlocked   bool      `json:"is_blocked"`
+	IsSymlink   bool      `json:"is_symlink,omitempty"`
+	Vanished    bool      `json:"vanished,omitempty"`
+	IsEmpty     bool      `json:"is_empty,omitempty"`
+	EntryType   string    `json:"entry_type,omitempty"`
+	BlockReason string    `json:"block_reason,omitempty"`
+	AccessError string    `json:"access_error,omitempty"`
+
+	ExtensionMismatch bool    `json:"extension_mismatch,omitempty"`
+	Entropy           float64 `json:"entropy,omitempty"`
+	Device            uint64  `json:"device,omitempty"`
+	Inode             uint64  `json:"inode,omitempty"`
+	Hash              string  `json:"hash,omitempty"`
+	QuickHash         string  `json:"quick_hash,omitempty"`
+	WorldWritable     bool    `json:"world_writable,omitempty"`
+	BrokenSymlink     bool    `json:"broken_symlink,omitempty"`
+
+	IsDuplicateLink bool `json:"is_duplicate_link,omitempty"`
+
+	InvalidName    bool   `json:"invalid_name,omitempty"`
+	EscapedPath    string `json:"escaped_path,omitempty"`
+	SuspiciousName bool   `json:"suspicious_name,omitempty"`
+	RealPath       string `json:"real_path,omitempty"`
+	Preview        string `json:"preview,omitempty"`
+
+	QuarantinedTo     string `json:"quarantined_to,omitempty"`
+	WouldQuarantineTo string `json:"would_quarantine_to,omitempty"`
+
+	ChangeType string `json:"change_type,omitempty"`
+
+	MatchedRuleGroups []string `json:"matched_rule_groups,omitempty"`
+
+	MatchedKeywords []string `json:"matched_keywords,omitempty"`
+
+	Note string `json:"note,omitempty"`
+}
+
+// withKeyStyle returns the value to encode for data in the given style
+func withKeyStyle(data ExportData, style KeyStyle) interface{} {
+	if style != KeyStyleSnake {
+		return data
+	}
+
+	// Een lege lijst wordt [] in plaats van null
+	files := make([]snakeFileInfo, 0, len(data.BlockedFiles))
+	for _, file := range data.BlockedFiles {
+		files = append(files, snakeFileInfo(file))
+	}
+	return snakeExportData{
+		Timestamp:    data.Timestamp,
+		Root:         data.Root,
+		TotalFiles:   data.TotalFiles,
+		BlockedFiles: files,
+		ScanDuration: data.ScanDuration,
+		BlockedCount: data.BlockedCount,
+		TotalSize:    data.TotalSize,
+		BlockedSize:  data.BlockedSize,
+
+		HashAlgorithm: data.HashAlgorithm,
+
+		ScanDurationHuman: data.ScanDurationHuman,
+	}
+}
@@ -0,0 +1,75 @@
+package jsonexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"filesystem-logger/internal/models"
+)
+
+// SizeNode is one entry of a size tree, in the nested name, value, children
+// layout that d3 hierarchies, sunbursts and flame graphs take as input
+type SizeNode struct {
+	Name     string      `json:"name"`
+	Value    int64       `json:"value"`
+	Children []*SizeNode `json:"children,omitempty"`
+}
+
+// BuildSizeTree nests the files of result below their directories. A file's
+// value is its size and a directory's value the total of everything below
+// it, as computed by models.AggregateDirSizes on a copy of the files, so
+// sizes already reported for directories by the scan are not counted twice.
+// Entries whose parent directory is not part of the result hang directly
+// below the root; when there is more than one such entry, a root named after
+// result.Root is added to hold them.
+func BuildSizeTree(result *models.ScanResult) *SizeNode {
+	files := append([]models.FileInfo(nil), result.Files...)
+	models.AggregateDirSizes(files)
+
+	nodes := make(map[string]*SizeNode, len(files))
+	for _, file := range files {
+		nodes[file.Path] = &SizeNode{Name: filepath.Base(file.Path), Value: file.Size}
+	}
+
+	var top []*SizeNode
+	for _, file := range files {
+		node := nodes[file.Path]
+		parent, ok := nodes[filepath.Dir(file.Path)]
+		if !ok || filepath.Dir(file.Path) == file.Path {
+			top = append(top, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, node := range nodes {
+		sortChildren(node)
+	}
+	if len(top) == 1 {
+		return top[0]
+	}
+	root := &SizeNode{Name: result.Root, Children: top}
+	for _, node := range top {
+		root.Value += node.Value
+	}
+	sortChildren(root)
+	return root
+}
+
+// sortChildren orders the children of node by name
+func sortChildren(node *SizeNode) {
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Name < node.Children[j].Name
+	})
+}
+
+// WriteSizeTree writes the size tree of result as JSON to w
+func WriteSizeTree(result *models.ScanResult, w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(BuildSizeTree(result)); err != nil {
+		return fmt.Errorf("failed to encode JSON: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package jsonexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"filesystem-logger/internal/models"
+)
+
+func TestBuildSizeTree(t *testing.T) {
+	// Directorygroottes zoals AggregateDirSizes ze rapporteert mogen niet dubbel tellen
+	result := &models.ScanResult{
+		Root: "/data",
+		Files: []models.FileInfo{
+			{Path: "/data", IsDirectory: true, Size: 1600},
+			{Path: "/data/a.txt", Size: 1000},
+			{Path: "/data/docs", IsDirectory: true, Size: 600},
+			{Path: "/data/docs/b.txt", Size: 400},
+			{Path: "/data/docs/c.txt", Size: 200},
+			{Path: "/data/empty", IsDirectory: true},
+		},
+		Progress: models.ScanProgress{ScannedSize: 1600},
+	}
+
+	tree := BuildSizeTree(result)
+
+	if tree.Name != "data" {
+		t.Errorf("Expected the root to be named data, got %q", tree.Name)
+	}
+	if tree.Value != result.Progress.ScannedSize {
+		t.Errorf("Expected the root value to equal the scanned size %d, got %d", result.Progress.ScannedSize, tree.Value)
+	}
+
+	tests := []struct {
+		name     string
+		node     *SizeNode
+		expected int64
+		children int
+	}{
+		{name: "a.txt", node: tree.Children[0], expected: 1000},
+		{name: "docs", node: tree.Children[1], expected: 600, children: 2},
+		{name: "empty", node: tree.Children[2], expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.node.Name != tt.name {
+				t.Fatalf("Expected node %s, got %s", tt.name, tt.node.Name)
+			}
+			if tt.node.Value != tt.expected {
+				t.Errorf("Expected value %d, got %d", tt.expected, tt.node.Value)
+			}
+			if len(tt.node.Children) != tt.children {
+				t.Errorf("Expected %d children, got %d", tt.children, len(tt.node.Children))
+			}
+		})
+	}
+
+	t.Run("Several top-level entries", func(t *testing.T) {
+		tree := BuildSizeTree(&models.ScanResult{
+			Root: "/data",
+			Files: []models.FileInfo{
+				{Path: "/data/a.txt", Size: 10},
+				{Path: "/data/b.txt", Size: 20},
+			},
+		})
+		if tree.Name != "/data" || tree.Value != 30 || len(tree.Children) != 2 {
+			t.Errorf("Expected a /data root of 30 bytes holding both files, got %+v", tree)
+		}
+	})
+
+	t.Run("JSON layout", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteSizeTree(result, &buf); err != nil {
+			t.Fatalf("WriteSizeTree failed: %v", err)
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to decode size tree: %v", err)
+		}
+		for _, key := range []string{"name", "value", "children"} {
+			if _, ok := decoded[key]; !ok {
+				t.Errorf("Expected key %q in the size tree", key)
+			}
+		}
+	})
+}
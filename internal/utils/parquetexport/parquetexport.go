@@ -0,0 +1,77 @@
+package parquetexport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"filesystem-logger/internal/models"
+)
+
+// row is one file of the export. Modification times are stored as
+// microseconds since the Unix epoch, annotated as UTC timestamps.
+type row struct {
+	Path        string    `parquet:"path"`
+	Size        int64     `parquet:"size"`
+	Extension   string    `parquet:"extension"`
+	MimeType    string    `parquet:"mimeType"`
+	ModTime     time.Time `parquet:"modTime,timestamp(microsecond)"`
+	IsBlocked   bool      `parquet:"isBlocked"`
+	BlockReason string    `parquet:"blockReason"`
+}
+
+// batchSize is the number of rows handed to the writer at once; the writer
+// splits them into pages and row groups itself
+const batchSize = 1024
+
+// ExportParquet writes one row per file of result, directories excluded,
+// to outputPath as a Snappy compressed Parquet file
+func ExportParquet(result *models.ScanResult, outputPath string) error {
+	// Zorg dat de output directory bestaat
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	writer := parquet.NewGenericWriter[row](file,
+		parquet.Compression(&parquet.Snappy),
+		parquet.CreatedBy("filesystem-logger", "", ""),
+	)
+
+	batch := make([]row, 0, batchSize)
+	for _, f := range result.Files {
+		if f.IsDirectory {
+			continue
+		}
+		batch = append(batch, row{
+			Path:        f.Path,
+			Size:        f.Size,
+			Extension:   f.Extension,
+			MimeType:    f.MimeType,
+			ModTime:     f.ModTime,
+			IsBlocked:   f.IsBlocked,
+			BlockReason: f.BlockReason,
+		})
+		if len(batch) == batchSize {
+			if _, err := writer.Write(batch); err != nil {
+				return fmt.Errorf("failed to write Parquet file: %v", err)
+			}
+			batch = batch[:0]
+		}
+	}
+	if _, err := writer.Write(batch); err != nil {
+		return fmt.Errorf("failed to write Parquet file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write Parquet file: %v", err)
+	}
+	return file.Close()
+}
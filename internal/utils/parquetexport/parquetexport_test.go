@@ -0,0 +1,109 @@
+package parquetexport
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"filesystem-logger/internal/models"
+)
+
+// readRows reads every row of the Parquet file at path
+func readRows(t *testing.T, path string) []row {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open Parquet file: %v", err)
+	}
+	defer f.Close()
+
+	reader := parquet.NewGenericReader[row](f)
+	defer reader.Close()
+	rows := make([]row, reader.NumRows())
+	if n, err := reader.Read(rows); err != nil && err != io.EOF {
+		t.Fatalf("Failed to read rows: %v", err)
+	} else if n != len(rows) {
+		t.Fatalf("Expected to read %d rows, got %d", len(rows), n)
+	}
+	return rows
+}
+
+func TestExportParquet(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	result := &models.ScanResult{
+		Files: []models.FileInfo{
+			{Path: "/test", Name: "test", IsDirectory: true},
+			{Path: "/test/setup.exe", Name: "setup.exe", Size: 2048, Extension: ".exe", MimeType: "application/octet-stream", ModTime: modTime, IsBlocked: true, BlockReason: "File type not allowed"},
+			{Path: "/test/notes.txt", Name: "notes.txt", Size: 100, Extension: ".txt", MimeType: "text/plain", ModTime: modTime},
+			{Path: "/test/big.iso", Name: "big.iso", Size: 4096, Extension: ".iso", ModTime: modTime, IsBlocked: true, BlockReason: "File size exceeds limit"},
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "out", "files.parquet")
+	if err := ExportParquet(result, outputPath); err != nil {
+		t.Fatalf("ExportParquet failed: %v", err)
+	}
+
+	rows := readRows(t, outputPath)
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows without the directory, got %d", len(rows))
+	}
+	expected := row{Path: "/test/notes.txt", Size: 100, Extension: ".txt", MimeType: "text/plain", ModTime: modTime}
+	if got := rows[1]; got.Path != expected.Path || got.Size != expected.Size || got.Extension != expected.Extension ||
+		got.MimeType != expected.MimeType || !got.ModTime.Equal(modTime) || got.IsBlocked {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+	if !rows[0].IsBlocked || rows[2].BlockReason != "File size exceeds limit" {
+		t.Errorf("Expected the blocked files with their reasons, got %+v", rows)
+	}
+
+	f, err := os.Open(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to open Parquet file: %v", err)
+	}
+	defer f.Close()
+	info, _ := f.Stat()
+	pf, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		t.Fatalf("Failed to open Parquet file: %v", err)
+	}
+	modTimeColumn, ok := pf.Schema().Lookup("modTime")
+	if !ok {
+		t.Fatal("Expected a modTime column")
+	}
+	if ts := modTimeColumn.Node.Type().LogicalType().Timestamp; ts == nil || !ts.IsAdjustedToUTC || ts.Unit.Micros == nil {
+		t.Errorf("Expected modTime as a UTC microsecond timestamp, got %v", modTimeColumn.Node.Type().LogicalType())
+	}
+}
+
+func TestExportParquetLarge(t *testing.T) {
+	const count = 50000
+	result := &models.ScanResult{Files: make([]models.FileInfo, count)}
+	for i := range result.Files {
+		result.Files[i] = models.FileInfo{
+			Path:      fmt.Sprintf("/data/dir%03d/file%05d.bin", i%100, i),
+			Size:      int64(i),
+			IsBlocked: i%7 == 0,
+		}
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "files.parquet")
+	if err := ExportParquet(result, outputPath); err != nil {
+		t.Fatalf("ExportParquet failed: %v", err)
+	}
+
+	rows := readRows(t, outputPath)
+	if len(rows) != count {
+		t.Fatalf("Expected %d rows, got %d", count, len(rows))
+	}
+	for i, r := range rows {
+		if r.Path != result.Files[i].Path || r.Size != int64(i) || r.IsBlocked != (i%7 == 0) {
+			t.Fatalf("Expected row %d to match %+v, got %+v", i, result.Files[i], r)
+		}
+	}
+}
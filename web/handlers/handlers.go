@@ -3,6 +3,9 @@ package handlers
 import (
 	"html/template"
 	"net/http"
+
+	"filesystem-logger/internal/api"
+	"filesystem-logger/internal/models"
 )
 
 func HomePage(w http.ResponseWriter, r *http.Request) {
@@ -15,7 +18,18 @@ func ScanPage(w http.ResponseWriter, r *http.Request) {
 	tmpl.Execute(w, nil)
 }
 
+// ResultsPage renders the result of the completed scan named by the id
+// query parameter, if any
 func ResultsPage(w http.ResponseWriter, r *http.Request) {
+	var result *models.ScanResult
+	if id := r.URL.Query().Get("id"); id != "" {
+		var ok bool
+		if result, ok = api.GetResultByID(id); !ok {
+			http.Error(w, "scan not found", http.StatusNotFound)
+			return
+		}
+	}
+
 	tmpl := template.Must(template.ParseFiles("web/templates/layout.html"))
-	tmpl.Execute(w, nil)
+	tmpl.Execute(w, result)
 }
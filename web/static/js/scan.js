@@ -7,32 +7,55 @@ document.addEventListener('alpine:init', () => {
             blockedFiles: 0
         },
         currentPath: '',
+        ws: null,
         
         startScan() {
             const config = {
-                path: this.$refs.path.value,
-                config: {
-                    maxFileSizeMB: 50,
-                    scanRecursively: true,
-                    exportBlockedToJSON: true
-                }
+                maxFileSizeMB: 50,
+                scanRecursively: true,
+                exportBlockedToJSON: true
             };
-            
-            fetch('/api/scan', {
-                method: 'POST',
-                body: JSON.stringify(config)
+
+            this.connectWebSocket(() => {
+                this.ws.send(JSON.stringify({
+                    action: 'start',
+                    path: this.$refs.path.value,
+                    config: config
+                }));
             });
-            
-            this.connectWebSocket();
         },
-        
-        connectWebSocket() {
-            const ws = new WebSocket('ws://localhost:8080/api/ws');
-            
-            ws.onmessage = (event) => {
-                const data = JSON.parse(event.data);
-                this.progress = data.progress;
-                this.currentPath = data.currentDirectory;
+
+        cancelScan() {
+            if (this.ws) {
+                this.ws.send(JSON.stringify({ action: 'cancel' }));
+            }
+        },
+
+        connectWebSocket(onOpen) {
+            if (this.ws && this.ws.readyState === WebSocket.OPEN) {
+                onOpen();
+                return;
+            }
+
+            const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
+            this.ws = new WebSocket(`${protocol}//${location.host}/api/ws`);
+            this.ws.onopen = onOpen;
+
+            this.ws.onmessage = (event) => {
+                const frame = JSON.parse(event.data);
+                this.status = frame.type;
+                if (frame.path) {
+                    this.currentPath = frame.path;
+                }
+                if (frame.progress) {
+                    this.progress = frame.progress;
+                }
+                if (frame.result) {
+                    this.progress = frame.result.progress;
+                }
+                if (frame.type === 'error') {
+                    console.error(frame.error);
+                }
             };
         }
     }));